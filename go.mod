@@ -4,4 +4,14 @@ go 1.23.1
 
 replace tc2mdc => ./tc2mdc
 
-require tc2mdc v0.0.0-00010101000000-000000000000
+require (
+	github.com/stretchr/testify v1.10.0
+	tc2mdc v0.0.0-00010101000000-000000000000
+)
+
+require (
+	github.com/BurntSushi/toml v1.5.0 // indirect
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
@@ -1,59 +1,392 @@
 package main
 
 import (
-	"bufio"
+	"errors"
+	"flag"
 	"fmt"
+	"io/fs"
 	"log"
 	"os"
-	"strconv"
+	"path/filepath"
+	"sort"
+	"strings"
 	"tc2mdc"
 )
 
+const usage = `Convert test comments to a MD (or another format) file.
+
+Usage:
+  tc2md [flags] [path ...]
+  tc2md lint [path ...]
+  tc2md coverage [-o file] [-format md|json] [-min percent] [path ...]
+
+Each path is a test file, a directory (its test files) or a pattern
+ending with "/..." to walk a directory recursively. Default path is "./...".
+Besides Go, test files of Java, Kotlin, Python, TypeScript and Rust are parsed.
+A document is written per test file, or per package with -package.
+The lint command reports tests without scenarios or steps and malformed markers,
+the coverage command reports how many tests are documented per package.
+
+Flags:
+`
+
+// Index document name without extension
+const indexName = "index"
+
+// document is a test data to write into a file
+type document struct {
+	path   string
+	source string // test file or directory of the package
+	data   *tc2mdc.TestData
+}
+
 func main() {
-	fmt.Println("Convert test comments to a MD file.")
+	if len(os.Args) > 1 && os.Args[1] == "lint" {
+		lint(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "coverage" {
+		coverage(os.Args[2:])
+		return
+	}
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
+	output := flag.String("o", ".", "output directory, or a single file with the extension of the format for all inputs")
+	format := flag.String("format", "md", "output format: "+strings.Join(tc2mdc.Formats(), ", "))
+	byPackage := flag.Bool("package", false, "write one document per package merging all its test files")
+	index := flag.Bool("index", false, "write "+indexName+" document linking all package documents, requires -package")
+	links := flag.Bool("links", false, "link tests to their source lines in the repository detected from .git")
+	repoURL := flag.String("repo", "", "web `URL` of the repository to link sources to, implies -links")
+	repoRef := flag.String("ref", "", "branch, tag or commit to link sources to, HEAD commit by default")
+	resultsPath := flag.String("results", "", "`file` with \"go test -json\" output to add test results, \"-\" for stdin")
+	flag.Parse()
 
-	testFiles := []string{
-		"./tc2mdc/tc2mdparser_test.go",
-		"./tc2mdc/tc2mdwriter_test.go",
+	paths := flag.Args()
+	if len(paths) == 0 {
+		paths = []string{"./..."}
+	}
+	writer, err := tc2mdc.GetWriter(*format)
+	if err != nil {
+		log.Fatal(err)
+	}
+	ext := writer.Extension()
+	singleFile := strings.HasSuffix(*output, ext)
+	if *index && (!*byPackage || singleFile) {
+		log.Fatal("-index requires -package and an output directory")
 	}
 
-	for i, testFile := range testFiles {
-		code := readTestFile(testFile)
+	testFiles, roots, err := collectTestFiles(paths)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if len(testFiles) == 0 {
+		log.Fatal("no test files found")
+	}
+
+	var documents []document
+	if *byPackage {
+		documents = getPackageDocuments(*output, ext, testFiles, roots)
+	} else {
+		for _, testFile := range testFiles {
+			path := getDocPath(*output, ext, testFile, roots[testFile])
+			documents = append(documents, document{path, testFile, parseTestFile(testFile)})
+		}
+	}
+	if err := checkDocPaths(documents); !singleFile && err != nil {
+		log.Fatal(err)
+	}
+
+	if *links || *repoURL != "" {
+		repo := getGitRepo(testFiles[0], *repoURL, *repoRef)
+		for _, doc := range documents {
+			tc2mdc.SetGitLinks(doc.data, repo)
+		}
+	}
+
+	if *resultsPath != "" {
+		results := readTestResults(*resultsPath)
+		for _, doc := range documents {
+			tc2mdc.ApplyTestResults(doc.data, results, getImportPath(doc.source))
+		}
+	}
 
-		testData, err := tc2mdc.Parse(code)
+	if singleFile {
+		var allData []*tc2mdc.TestData
+		for _, doc := range documents {
+			allData = append(allData, doc.data)
+		}
+		saveToFile(*output, writer.WriteAll(allData))
+		return
+	}
+	for _, doc := range documents {
+		saveToFile(doc.path, writer.Write(doc.data))
+	}
+	if *index {
+		saveIndex(*output, writer, documents)
+	}
+}
+
+// getGitRepo detects the repository of the test file, "repoURL" and "ref" override detected values.
+func getGitRepo(testFile string, repoURL string, ref string) *tc2mdc.GitRepo {
+	repo, err := tc2mdc.DetectGitRepo(filepath.Dir(testFile))
+	if err == nil && repoURL == "" && ref == "" {
+		return repo
+	}
+	if repoURL == "" || ref == "" {
+		if err != nil {
+			log.Fatal(err)
+		}
+		if repoURL == "" {
+			repoURL = repo.BaseURL()
+		}
+		if ref == "" {
+			ref = repo.Ref()
+		}
+	}
+
+	root := "."
+	if repo != nil {
+		root = repo.Root()
+	}
+	return tc2mdc.NewGitRepo(root, repoURL, ref)
+}
+
+// getImportPath returns the import path of the Go package of the source file or directory,
+// empty if there is no go.mod to resolve it.
+func getImportPath(source string) string {
+	dir := source
+	if info, err := os.Stat(source); err == nil && !info.IsDir() {
+		dir = filepath.Dir(source)
+	}
+	importPath, err := tc2mdc.GetImportPath(dir)
+	if err != nil {
+		return ""
+	}
+	return importPath
+}
+
+func readTestResults(path string) tc2mdc.TestResults {
+	reader := os.Stdin
+	if path != "-" {
+		file, err := os.Open(path)
 		if err != nil {
 			log.Fatal(err)
 		}
+		defer file.Close()
+		reader = file
+	}
 
-		mdText := tc2mdc.Write(testData)
-		saveToMDFile("scenario"+strconv.Itoa(i)+".md", mdText)
+	results, err := tc2mdc.ParseTestResults(reader)
+	if err != nil {
+		log.Fatal(err)
 	}
+	return results
 }
 
-func readTestFile(path string) []string {
-	file, err := os.Open(path)
+func parseTestFile(testFile string) *tc2mdc.TestData {
+	code, err := os.ReadFile(testFile)
 	if err != nil {
 		log.Fatal(err)
 	}
-	defer file.Close()
 
-	scanner := bufio.NewScanner(file)
-	var code []string
-	for scanner.Scan() {
-		code = append(code, scanner.Text())
+	testData, err := getGrammar(testFile).ParseFile(testFile, code)
+	if testData == nil {
+		log.Fatal(err)
+	}
+	var parseErrors tc2mdc.ParseErrors
+	if errors.As(err, &parseErrors) {
+		for _, parseError := range parseErrors {
+			log.Println(parseError) // malformed markers are skipped
+		}
+	}
+	logStepIssues(testData.Methods())
+	return testData
+}
+
+// Grammars of markers by directories of test files
+var grammars = make(map[string]tc2mdc.Grammar)
+
+// getGrammar returns the grammar of the .tc2md.yaml or .tc2md.toml config file found
+// in the directory of the test file or in its parents, the default one if there is no file.
+func getGrammar(testFile string) tc2mdc.Grammar {
+	dir := filepath.Dir(testFile)
+	if grammar, ok := grammars[dir]; ok {
+		return grammar
+	}
+	grammar, configPath, err := tc2mdc.FindGrammar(dir)
+	if err != nil {
+		log.Fatal(err)
 	}
-	return code
+	if configPath != "" {
+		log.Printf("Markers of %s are read from %s", dir, configPath)
+	}
+	grammars[dir] = grammar
+	return grammar
 }
 
-func saveToMDFile(path string, mdText []string) {
-	mdFile, err := os.Create(path)
+// logStepIssues logs issues of the order of GIVEN/WHEN/THEN steps of test methods and their subtests.
+func logStepIssues(methods []tc2mdc.TestMethod) {
+	for _, method := range methods {
+		for _, issue := range tc2mdc.ValidateSteps(method.Steps()) {
+			log.Printf("%s:%d: %s: %s", method.File(), method.Line(), method.Name(), issue)
+		}
+		logStepIssues(method.Subtests())
+	}
+}
+
+// getPackageDocuments parses test files and merges them per package of the same directory,
+// each package document is named by the package.
+func getPackageDocuments(outputDir string, ext string, testFiles []string, roots map[string]string) []document {
+	var dirs []string
+	filesByDir := make(map[string][]*tc2mdc.TestData)
+	rootsByDir := make(map[string]string)
+	for _, testFile := range testFiles {
+		dir := filepath.Dir(testFile)
+		if _, ok := filesByDir[dir]; !ok {
+			dirs = append(dirs, dir)
+			rootsByDir[dir] = roots[testFile]
+		}
+		filesByDir[dir] = append(filesByDir[dir], parseTestFile(testFile))
+	}
+
+	var documents []document
+	for _, dir := range dirs {
+		for _, packageData := range tc2mdc.MergePackages(filesByDir[dir]) {
+			path := filepath.Join(outputDir, getRelDir(dir, rootsByDir[dir]), packageData.PackageName()+ext)
+			documents = append(documents, document{path, dir, packageData})
+		}
+	}
+	return documents
+}
+
+func saveIndex(outputDir string, writer tc2mdc.Writer, documents []document) {
+	var packages []*tc2mdc.TestData
+	var links []string
+	for _, doc := range documents {
+		link, err := filepath.Rel(outputDir, doc.path)
+		if err != nil {
+			log.Fatal(err)
+		}
+		packages = append(packages, doc.data)
+		links = append(links, filepath.ToSlash(link))
+	}
+	text := writer.WriteIndex(packages, links)
+	if text == nil {
+		log.Println("No index in the format, skipped")
+		return
+	}
+	saveToFile(filepath.Join(outputDir, indexName+writer.Extension()), text)
+}
+
+// collectTestFiles expands files, directories and "dir/..." patterns into a sorted list of test files,
+// roots of test files are directories they are found in by the paths (the directory of a file path).
+func collectTestFiles(paths []string) ([]string, map[string]string, error) {
+	roots := make(map[string]string)
+	for _, path := range paths {
+		recursive := false
+		if path == "..." || strings.HasSuffix(path, "/...") {
+			recursive = true
+			path = strings.TrimSuffix(strings.TrimSuffix(path, "..."), "/")
+			if path == "" {
+				path = "."
+			}
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !info.IsDir() {
+			path = filepath.Clean(path)
+			if _, ok := roots[path]; !ok {
+				roots[path] = filepath.Dir(path)
+			}
+			continue
+		}
+
+		err = filepath.WalkDir(path, func(filePath string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if entry.IsDir() {
+				if filePath != path && (!recursive || isSkippedDir(entry.Name())) {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if _, ok := roots[filePath]; !ok && tc2mdc.IsTestFile(filePath) {
+				roots[filePath] = path
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var testFiles []string
+	for testFile := range roots {
+		testFiles = append(testFiles, testFile)
+	}
+	sort.Strings(testFiles)
+	return testFiles, roots, nil
+}
+
+// isSkippedDir follows the go tool: hidden, "_" prefixed, "testdata" and "vendor" dirs are ignored.
+func isSkippedDir(name string) bool {
+	return strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") || name == "testdata" || name == "vendor"
+}
+
+// getDocPath returns the document path for the test file - its name with the extension of the format,
+// placed in the output directory under the same relative directory as the source, see getRelDir().
+func getDocPath(outputDir string, ext string, testFile string, root string) string {
+	docName := strings.TrimSuffix(filepath.Base(testFile), filepath.Ext(testFile)) + ext
+	return filepath.Join(outputDir, getRelDir(filepath.Dir(testFile), root), docName)
+}
+
+// getRelDir returns the source directory to place its documents under in the output directory,
+// a directory out of the working tree is relative to the root it is found in.
+func getRelDir(dir string, root string) string {
+	if !isOutsideDir(dir) {
+		return dir
+	}
+	if relDir, err := filepath.Rel(root, dir); err == nil && !isOutsideDir(relDir) {
+		return relDir
+	}
+	return "" // out of the root too, keep the name only
+}
+
+// isOutsideDir checks the path is out of the current directory: absolute or starting with "..".
+func isOutsideDir(path string) bool {
+	return filepath.IsAbs(path) || path == ".." || strings.HasPrefix(path, ".."+string(filepath.Separator))
+}
+
+// checkDocPaths returns an error if documents of different sources have the same path.
+func checkDocPaths(documents []document) error {
+	sources := make(map[string]string)
+	for _, doc := range documents {
+		if source, ok := sources[doc.path]; ok {
+			return errors.New("documents of " + source + " and " + doc.source + " have the same path " + doc.path)
+		}
+		sources[doc.path] = doc.source
+	}
+	return nil
+}
+
+func saveToFile(path string, text []string) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		log.Fatal(err)
+	}
+	file, err := os.Create(path)
 	if err != nil {
 		log.Fatal(err)
 	}
-	defer mdFile.Close()
+	defer file.Close()
 
-	for _, line := range mdText {
-		_, err := mdFile.WriteString(line + "\n")
+	for _, line := range text {
+		_, err := file.WriteString(line + "\n")
 		if err != nil {
 			log.Fatal(err)
 		}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollectTestFiles(t *testing.T) {
+	// > Command line
+	// # collectTestFiles() finds test files of paths, "/..." patterns walk directories recursively
+	// ## GIVEN a directory with test files of the root, 'sub' and skipped directories
+	root := t.TempDir()
+	for _, name := range []string{
+		"a_test.go", "a.go", "sub/b_test.go",
+		".hidden/c_test.go", "_tmp/d_test.go", "testdata/e_test.go", "vendor/f_test.go",
+	} {
+		path := filepath.Join(root, name)
+		require.Nil(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.Nil(t, os.WriteFile(path, []byte("package a\n"), 0o644))
+	}
+
+	// ## WHEN collectTestFiles() of the directory and of its test file
+	testFiles, roots, err := collectTestFiles([]string{root, filepath.Join(root, "a_test.go")})
+	// ## THEN test files of the directory only are found once with the directory as the root
+	require.Nil(t, err, "must be no error")
+	require.Equal(t, []string{filepath.Join(root, "a_test.go")}, testFiles)
+	require.Equal(t, map[string]string{filepath.Join(root, "a_test.go"): root}, roots)
+
+	// ## WHEN collectTestFiles() of the pattern "<dir>/..."
+	testFiles, roots, err = collectTestFiles([]string{root + "/..."})
+	// ## THEN test files in subdirectories are found sorted
+	require.Nil(t, err, "must be no error")
+	// - hidden, "_" prefixed, 'testdata' and 'vendor' directories are skipped
+	require.Equal(t, []string{filepath.Join(root, "a_test.go"), filepath.Join(root, "sub", "b_test.go")}, testFiles)
+	// - the root of all test files is the walked directory
+	require.Equal(t, root, roots[filepath.Join(root, "sub", "b_test.go")])
+
+	// ## WHEN collectTestFiles() of a test file
+	_, roots, err = collectTestFiles([]string{filepath.Join(root, "sub", "b_test.go")})
+	// ## THEN the root is the directory of the file
+	require.Nil(t, err, "must be no error")
+	require.Equal(t, filepath.Join(root, "sub"), roots[filepath.Join(root, "sub", "b_test.go")])
+
+	// ## WHEN collectTestFiles() of the pattern "..." in the directory
+	workDir, err := os.Getwd()
+	require.Nil(t, err, "must be no error")
+	require.Nil(t, os.Chdir(root))
+	t.Cleanup(func() { _ = os.Chdir(workDir) })
+	testFiles, _, err = collectTestFiles([]string{"..."})
+	// ## THEN paths of test files are relative
+	require.Nil(t, err, "must be no error")
+	require.Equal(t, []string{"a_test.go", filepath.Join("sub", "b_test.go")}, testFiles)
+
+	// ## WHEN collectTestFiles() of a missing path
+	_, _, err = collectTestFiles([]string{filepath.Join(root, "missing")})
+	// ## THEN there is an error
+	require.NotNil(t, err, "must be an error")
+}
+
+func TestGetDocPath(t *testing.T) {
+	// > Command line
+	// # getDocPath() returns the document of a test file under its relative directory in the output one
+	// ## GIVEN test files found in roots and expected documents in 'docs' with ".html" extension:
+	var paths = []struct{ testFile, root, expected string }{
+		// - 'a_test.go' of '.' is 'docs/a_test.html'
+		{"a_test.go", ".", filepath.Join("docs", "a_test.html")},
+		// - 'pkg/a_test.go' of '.' is 'docs/pkg/a_test.html'
+		{filepath.Join("pkg", "a_test.go"), ".", filepath.Join("docs", "pkg", "a_test.html")},
+		// - '../res/pkg/a_test.go' and '../res/pkg2/a_test.go' of '../res' are 'docs/pkg/a_test.html'
+		// and 'docs/pkg2/a_test.html'
+		{filepath.Join("..", "res", "pkg", "a_test.go"), filepath.Join("..", "res"), filepath.Join("docs", "pkg", "a_test.html")},
+		{filepath.Join("..", "res", "pkg2", "a_test.go"), filepath.Join("..", "res"), filepath.Join("docs", "pkg2", "a_test.html")},
+		// - '../res/a_test.go' of its directory is 'docs/a_test.html'
+		{filepath.Join("..", "res", "a_test.go"), filepath.Join("..", "res"), filepath.Join("docs", "a_test.html")},
+	}
+
+	for _, path := range paths {
+		// ## WHEN getDocPath()
+		// ## THEN the path is as expected
+		require.Equal(t, path.expected, getDocPath("docs", ".html", path.testFile, path.root), path.testFile)
+	}
+}
+
+func TestGetRelDir(t *testing.T) {
+	// > Command line
+	// # getRelDir() returns the directory of sources in the working tree, out of it the directory relative to the root
+	// ## GIVEN directories, the root '../res' and expected results:
+	root := filepath.Join("..", "res")
+	var dirs = map[string]string{
+		// - '.' and 'pkg/sub' are kept
+		".": ".", filepath.Join("pkg", "sub"): filepath.Join("pkg", "sub"),
+		// - '../res' and '../res/pkg' are relative to the root
+		root: ".", filepath.Join(root, "pkg"): "pkg",
+		// - '..' and '../other' are out of the root too
+		"..": "", filepath.Join("..", "other"): "",
+		// - '..pkg' is a directory of the working tree
+		"..pkg": "..pkg",
+	}
+	// - an absolute path out of the root is out of both
+	absDir, err := filepath.Abs("pkg")
+	require.Nil(t, err, "must be no error")
+	dirs[absDir] = ""
+
+	for dir, expected := range dirs {
+		// ## WHEN getRelDir()
+		// ## THEN the result is as expected
+		require.Equal(t, expected, getRelDir(dir, root), dir)
+	}
+}
+
+func TestCheckDocPaths(t *testing.T) {
+	// > Command line
+	// # checkDocPaths() returns an error of documents of different sources with the same path
+	// ## GIVEN documents 'docs/a_test.md' of 'a/a_test.go' and 'docs/b_test.md'
+	var documents = []document{
+		{path: filepath.Join("docs", "a_test.md"), source: filepath.Join("a", "a_test.go")},
+		{path: filepath.Join("docs", "b_test.md"), source: filepath.Join("a", "b_test.go")},
+	}
+	// ## WHEN checkDocPaths()
+	// ## THEN there is no error
+	require.Nil(t, checkDocPaths(documents), "must be no error")
+
+	// ## WHEN checkDocPaths() with 'docs/a_test.md' of 'b/a_test.go'
+	err := checkDocPaths(append(documents, document{path: filepath.Join("docs", "a_test.md"),
+		source: filepath.Join("b", "a_test.go")}))
+	// ## THEN the error has both sources and the path
+	require.EqualError(t, err, "documents of "+filepath.Join("a", "a_test.go")+" and "+filepath.Join("b", "a_test.go")+
+		" have the same path "+filepath.Join("docs", "a_test.md"))
+}
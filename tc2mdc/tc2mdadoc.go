@@ -0,0 +1,135 @@
+package tc2mdc
+
+import "strings"
+
+// asciiDocMarkup is the AsciiDoc format. Headers are discrete to keep the levels of MD documents,
+// each block is preceded by an empty line.
+type asciiDocMarkup struct{}
+
+func (asciiDocMarkup) appendDocument(title string, body []string, adocText *[]string) {
+	for len(body) > 0 && body[0] == "" {
+		body = body[1:]
+	}
+	*adocText = append(*adocText, body...)
+}
+
+func (asciiDocMarkup) appendTitle(title string, adocText *[]string) {
+	*adocText = append(*adocText, "", "[discrete]", "= "+title)
+}
+
+func (asciiDocMarkup) appendPackage(packageName string, adocText *[]string) {
+	*adocText = append(*adocText, "", "[discrete]", "[["+getTopAnchor(packageName)+"]]", "== `"+packageName+"`")
+}
+
+func (asciiDocMarkup) appendResultsSummary(counts map[string]int, adocText *[]string) {
+	*adocText = append(*adocText, "", getResultsSummary(counts, getResultBadge))
+}
+
+func (asciiDocMarkup) appendTOC(lines []TOCLine, adocText *[]string) {
+	*adocText = append(*adocText, "")
+	for _, line := range lines {
+		*adocText = append(*adocText, ". <<"+strings.TrimPrefix(line.link, "#")+","+line.caption+">>")
+	}
+}
+
+func (asciiDocMarkup) appendSection(section string, adocText *[]string) {
+	*adocText = append(*adocText, "", "[discrete]", "=== "+section)
+}
+
+func (asciiDocMarkup) appendBackground(adocText *[]string) {
+	*adocText = append(*adocText, "", "[discrete]", "==== Background")
+}
+
+func (asciiDocMarkup) appendFunc(name string, anchor string, depth int, adocText *[]string) {
+	if depth == 0 {
+		*adocText = append(*adocText, "", "'''")
+	}
+	*adocText = append(*adocText, "", "[discrete]", "[["+anchor+"]]", getASCIIDocHeaderPrefix(4+depth)+"`"+name+"`")
+}
+
+func (asciiDocMarkup) appendFuncInfo(result TestResult, gitLink string, adocText *[]string) {
+	var info []string
+	if result.action != "" {
+		info = append(info, getResultBadge(result.action)+" "+getElapsed(result))
+	}
+	if gitLink != "" {
+		info = append(info, gitLink+"[view source]")
+	}
+	if info != nil {
+		*adocText = append(*adocText, "", strings.Join(info, " · "))
+	}
+}
+
+func (asciiDocMarkup) appendTags(tags []string, adocText *[]string) {
+	*adocText = append(*adocText, "", "____", strings.Join(tags, ", "), "____")
+}
+
+func (asciiDocMarkup) appendScenario(scenario string, depth int, adocText *[]string) {
+	*adocText = append(*adocText, "", "[discrete]", getASCIIDocHeaderPrefix(3+depth)+scenario)
+}
+
+func (asciiDocMarkup) appendDescription(description []string, adocText *[]string) {
+	*adocText = append(*adocText, "")
+	*adocText = append(*adocText, description...)
+}
+
+// appendSteps adds 'GWT' steps as headers and consecutive indented steps as a list.
+func (asciiDocMarkup) appendSteps(steps []TestStep, depth int, adocText *[]string) {
+	isListOpen := false
+	for _, step := range steps {
+		if step.kind.IsGWT() {
+			*adocText = append(*adocText, "", "[discrete]", getASCIIDocHeaderPrefix(4+depth)+getStepText(step, getASCIIDocBold))
+			isListOpen = false
+			continue
+		}
+		if !isListOpen {
+			*adocText = append(*adocText, "")
+			isListOpen = true
+		}
+		*adocText = append(*adocText, strings.Repeat("*", int(step.kind))+" "+step.comment)
+	}
+}
+
+func (asciiDocMarkup) appendCase(testCase TestCase, depth int, adocText *[]string) {
+	*adocText = append(*adocText, "", "[discrete]", getASCIIDocHeaderPrefix(5+depth)+testCase.name)
+	if len(testCase.fields) == 0 {
+		return
+	}
+	*adocText = append(*adocText, "", `[cols="1,3",options="header"]`, "|===", "|Field |Value")
+	for _, field := range testCase.fields {
+		*adocText = append(*adocText, "|"+field.name+" |`+"+strings.ReplaceAll(field.value, "|", "\\|")+"+`")
+	}
+	*adocText = append(*adocText, "|===")
+}
+
+func (asciiDocMarkup) appendFailureOutput(output []string, adocText *[]string) {
+	*adocText = append(*adocText, "", ".Failure output", "[%collapsible]", "====", "----")
+	*adocText = append(*adocText, output...)
+	*adocText = append(*adocText, "----", "====")
+}
+
+func (asciiDocMarkup) appendOutput(output []string, adocText *[]string) {
+	*adocText = append(*adocText, "", ".Output", "----")
+	*adocText = append(*adocText, output...)
+	*adocText = append(*adocText, "----")
+}
+
+func (asciiDocMarkup) appendFuncEnd(topAnchor string, adocText *[]string) {
+	*adocText = append(*adocText, "", "link:#"+topAnchor+"[top]")
+}
+
+func (asciiDocMarkup) appendIndex(items []indexItem, adocText *[]string) {
+	*adocText = append(*adocText, "[discrete]", "== Packages", "")
+	for _, item := range items {
+		*adocText = append(*adocText, "* xref:"+item.link+"["+item.caption+"] - "+getTestsCount(item.count))
+	}
+}
+
+func getASCIIDocBold(text string) string {
+	return "*" + text + "*"
+}
+
+// getASCIIDocHeaderPrefix returns the prefix of a header of the level, the deepest level is 6.
+func getASCIIDocHeaderPrefix(level int) string {
+	return strings.Repeat("=", min(level, 6)) + " "
+}
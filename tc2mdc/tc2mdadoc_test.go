@@ -0,0 +1,72 @@
+package tc2mdc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestASCIIDocMethod(t *testing.T) {
+	// > Write to AsciiDoc
+	// # AsciiDoc writer returns discrete headers with anchors, steps as lists and cases as tables
+	// ## GIVEN - testData: "packageName" = 'pkg' with 1 method 'TestA':
+	var testData = &TestData{packageName: "pkg"}
+	testData.methods = []TestMethod{{
+		name: "TestA",
+		// - "tags" = 'T1', "scenario" = 'Scenario'
+		tags:     []string{"T1"},
+		scenario: "Scenario",
+		// - "steps": 'GWT' 'WHEN act', common 'Step1', indented 'Step2'
+		steps: []TestStep{{GWT, "WHEN act"}, {Common, "Step1"}, {Indented, "Step2"}},
+		// - "cases": 'c1' with field {'in', '"a|b"'}
+		cases: []TestCase{{name: "c1", fields: []TestField{{"in", `"a|b"`}}}},
+	}}
+	// - TOC line {0, 'Scenario', '#testa'}
+	testData.toc = map[string]TOCLine{"TestA": {index: 0, caption: "Scenario", link: "#testa"}}
+	writer, err := GetWriter("adoc")
+	require.Nil(t, err, "must be no error")
+
+	// ## WHEN Write()
+	adocText := writer.Write(testData)
+
+	// ## THEN - AsciiDoc text is:
+	require.Equal(t, []string{
+		"[discrete]",
+		"[[pkg]]",
+		"== `pkg`",
+		"",
+		". <<testa,Scenario>>",
+		"",
+		"'''",
+		"",
+		"[discrete]",
+		"[[testa]]",
+		"==== `TestA`",
+		"",
+		"____",
+		"T1",
+		"____",
+		"",
+		"[discrete]",
+		"=== Scenario",
+		"",
+		"[discrete]",
+		"==== WHEN act",
+		"",
+		"* Step1",
+		"** Step2",
+		"",
+		"[discrete]",
+		"===== c1",
+		"",
+		`[cols="1,3",options="header"]`,
+		"|===",
+		"|Field |Value",
+		"|in |`+\"a\\|b\"+`",
+		"|===",
+		"",
+		"link:#pkg[top]",
+	}, adocText)
+	// - extension is '.adoc'
+	require.Equal(t, ".adoc", writer.Extension())
+}
@@ -0,0 +1,370 @@
+package tc2mdc
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// parseGoFile parses a complete Go file with go/parser; markers are attached to a test func
+// by the position of comments within its body. Markers of "SetupSuite" and "SetupTest" methods of
+// a testify suite are the background of its "TestXxx" methods, markers of "TestMain" and of funcs with
+// the "// @background" annotation are the background of all tests. Syntax errors are returned without data,
+// malformed markers of test funcs are returned as ParseErrors with the data.
+func parseGoFile(filename string, src []byte, grammar *markerGrammar) (*TestData, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+	if err != nil {
+		return nil, getSyntaxErrors(filename, err)
+	}
+
+	var testData = new(TestData)
+	testData.packageName = file.Name.Name
+	testingName := getImportName(file, "testing")
+
+	for _, comment := range getFileLevelComments(file) {
+		parseTitle(comment.Text, grammar, testData)
+	}
+
+	var errs ParseErrors
+	for _, decl := range file.Decls {
+		funcDecl, ok := decl.(*ast.FuncDecl)
+		if !ok || funcDecl.Body == nil {
+			continue
+		}
+		method := TestMethod{name: funcDecl.Name.Name, file: filename, line: fset.Position(funcDecl.Pos()).Line}
+		suite, isSuiteMethod := getSuiteReceiver(funcDecl)
+		isSetup := isSuiteMethod && containsString(suiteSetups, method.name)
+		isBackground := !isSuiteMethod && isBackgroundFunc(funcDecl, testingName)
+		switch {
+		case isSuiteMethod && isTestName(method.name, funcPrefixes[TestFunc]):
+			{
+				method.name, method.suite = suite+"."+method.name, suite
+				testData.getSuite(suite)
+			}
+		case !isSetup && !isBackground:
+			{
+				if method.kind, ok = getTestFuncKind(funcDecl, testingName); !ok {
+					continue
+				}
+			}
+		}
+		parseDocComment(funcDecl.Doc, grammar, &method)
+		parseTestBody(funcDecl.Body, file, fset, src, grammar, &method)
+		switch {
+		case isBackground:
+			{
+				testData.background = append(testData.background, method.steps...)
+			}
+		case isSetup:
+			{
+				testData.getSuite(suite).addBackground(method.name, method.steps)
+			}
+		default:
+			{
+				// a func running suites is not a test of its own, tests are methods of the suites
+				suiteNames := getSuiteRuns(funcDecl.Body)
+				for _, suiteName := range suiteNames {
+					testData.addSuiteRunner(suiteName, method.name)
+				}
+				if len(suiteNames) == 0 {
+					testData.methods = append(testData.methods, method)
+				}
+			}
+		}
+
+		if funcDecl.Doc != nil {
+			for _, comment := range funcDecl.Doc.List {
+				appendMarkerErrors(comment, true, filename, fset, grammar, &errs)
+			}
+		}
+		for _, comment := range getComments(file, funcDecl.Body.Lbrace, getMarkersEnd(file, funcDecl.Body, method.kind)) {
+			appendMarkerErrors(comment, false, filename, fset, grammar, &errs)
+		}
+	}
+	return testData, errs.err()
+}
+
+// parseTestBody parses markers and table cases of the test func body. Each "t.Run("name", func...)" call
+// is a subtest with its own markers, so comments inside of its func are not the markers of the parent.
+func parseTestBody(body *ast.BlockStmt, file *ast.File, fset *token.FileSet, src []byte, grammar *markerGrammar,
+	testMethod *TestMethod) {
+	var subtestBodies []*ast.BlockStmt
+	ast.Inspect(body, func(node ast.Node) bool {
+		call, ok := node.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		name, subtestBody := getSubtest(call)
+		if subtestBody == nil {
+			return true
+		}
+		subtest := TestMethod{name: testMethod.name + "/" + name, file: testMethod.file, line: fset.Position(call.Pos()).Line}
+		parseTestBody(subtestBody, file, fset, src, grammar, &subtest)
+		testMethod.subtests = append(testMethod.subtests, subtest)
+		subtestBodies = append(subtestBodies, subtestBody)
+		return false
+	})
+
+	for _, comment := range getComments(file, body.Lbrace, getMarkersEnd(file, body, testMethod.kind)) {
+		if isInsideBlocks(subtestBodies, comment.Pos()) {
+			continue
+		}
+		if strings.HasPrefix(comment.Text, BCStart) {
+			parseBlockComment(comment.Text, grammar, testMethod)
+		} else {
+			parseOneLineComment(comment.Text, grammar, testMethod)
+		}
+	}
+	testMethod.cases = parseTableCases(body, subtestBodies, fset, src)
+	if output := getExampleOutput(file, body); testMethod.kind == ExampleFunc && output != nil {
+		testMethod.output = getOutputLines(output)
+	}
+}
+
+// Start of the output comment of an example, e.g. "// Output:" or "// Unordered output:"
+var reOutput = regexp.MustCompile(`(?i)^[[:space:]]*(unordered )?output:`)
+
+// getExampleOutput returns the last comment group of the body if it is the output comment of an example.
+func getExampleOutput(file *ast.File, body *ast.BlockStmt) *ast.CommentGroup {
+	var last *ast.CommentGroup
+	for _, group := range file.Comments {
+		if group.Pos() > body.Lbrace && group.End() < body.Rbrace {
+			last = group
+		}
+	}
+	if last == nil || !reOutput.MatchString(last.Text()) {
+		return nil
+	}
+	return last
+}
+
+// getOutputLines returns the expected output lines of an example: the rest of the "Output:" line
+// and the following lines of the comment.
+func getOutputLines(output *ast.CommentGroup) []string {
+	text := output.Text()
+	text = strings.TrimSuffix(text[len(reOutput.FindString(text)):], "\n")
+	lines := strings.Split(strings.TrimLeft(text, " "), "\n")
+	if lines[0] == "" {
+		lines = lines[1:]
+	}
+	return lines
+}
+
+// getMarkersEnd returns the end of markers of the body: the output comment of an example is not parsed.
+func getMarkersEnd(file *ast.File, body *ast.BlockStmt, kind FuncKind) token.Pos {
+	if output := getExampleOutput(file, body); kind == ExampleFunc && output != nil {
+		return output.Pos()
+	}
+	return body.Rbrace
+}
+
+// getSubtest returns the name and body of a "t.Run("name", func(t *testing.T) {...})" call,
+// spaces of the name are replaced with '_' as "go test" does.
+func getSubtest(call *ast.CallExpr) (string, *ast.BlockStmt) {
+	if fun, ok := call.Fun.(*ast.SelectorExpr); !ok || fun.Sel.Name != "Run" || len(call.Args) != 2 {
+		return "", nil
+	}
+	nameLit, ok := call.Args[0].(*ast.BasicLit)
+	if !ok || nameLit.Kind != token.STRING {
+		return "", nil
+	}
+	funcLit, ok := call.Args[1].(*ast.FuncLit)
+	if !ok {
+		return "", nil
+	}
+	name, err := strconv.Unquote(nameLit.Value)
+	if err != nil {
+		return "", nil
+	}
+	return strings.ReplaceAll(name, " ", "_"), funcLit.Body
+}
+
+func isInsideBlocks(blocks []*ast.BlockStmt, pos token.Pos) bool {
+	for _, block := range blocks {
+		if pos > block.Lbrace && pos < block.Rbrace {
+			return true
+		}
+	}
+	return false
+}
+
+// Annotation of a doc comment of a func with the background steps of all tests
+const backgroundAnnotation = "@background"
+
+// isBackgroundFunc checks the func is "func TestMain(m *testing.M)" or a func with
+// the "// @background" line in its doc comment, e.g. a setup helper.
+func isBackgroundFunc(funcDecl *ast.FuncDecl, testingName string) bool {
+	if funcDecl.Recv == nil && funcDecl.Name.Name == "TestMain" && len(funcDecl.Type.Params.List) == 1 &&
+		isSelectorPointer(funcDecl.Type.Params.List[0].Type, testingName, "M") {
+		return true
+	}
+	if funcDecl.Doc == nil {
+		return false
+	}
+	for _, comment := range funcDecl.Doc.List {
+		if strings.HasPrefix(comment.Text, OLC) && strings.TrimSpace(comment.Text[len(OLC):]) == backgroundAnnotation {
+			return true
+		}
+	}
+	return false
+}
+
+// Param types of test funcs by kind, an example has no params
+var funcParams = map[FuncKind]string{TestFunc: "T", BenchmarkFunc: "B", FuzzFunc: "F"}
+
+// getTestFuncKind returns the kind of the func if it is "func TestXxx(name *testing.T)",
+// "func BenchmarkXxx(name *testing.B)", "func FuzzXxx(name *testing.F)" or "func ExampleXxx()"
+// as the go tool expects them.
+func getTestFuncKind(funcDecl *ast.FuncDecl, testingName string) (FuncKind, bool) {
+	if funcDecl.Recv != nil || funcDecl.Type.TypeParams != nil || funcDecl.Type.Results != nil {
+		return TestFunc, false
+	}
+	kind := getFuncKind(funcDecl.Name.Name)
+	if !isTestName(funcDecl.Name.Name, funcPrefixes[kind]) {
+		return kind, false
+	}
+	params := funcDecl.Type.Params.List
+	if kind == ExampleFunc {
+		return kind, len(params) == 0
+	}
+	if len(params) != 1 || len(params[0].Names) > 1 {
+		return kind, false
+	}
+	return kind, isSelectorPointer(params[0].Type, testingName, funcParams[kind])
+}
+
+// isTestName checks the name is the prefix followed by nothing or by not a lower case letter.
+func isTestName(name string, prefix string) bool {
+	if !strings.HasPrefix(name, prefix) {
+		return false
+	}
+	if len(name) == len(prefix) {
+		return true
+	}
+	r, _ := utf8.DecodeRuneInString(name[len(prefix):])
+	return !unicode.IsLower(r)
+}
+
+// isSelectorPointer checks the expression is "*pkg.Type".
+func isSelectorPointer(expr ast.Expr, pkg string, typeName string) bool {
+	star, ok := expr.(*ast.StarExpr)
+	if !ok {
+		return false
+	}
+	selector, ok := star.X.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	ident, ok := selector.X.(*ast.Ident)
+	return ok && ident.Name == pkg && selector.Sel.Name == typeName
+}
+
+// getImportName returns the name the import path is referred by in the file.
+func getImportName(file *ast.File, path string) string {
+	for _, spec := range file.Imports {
+		importPath, err := strconv.Unquote(spec.Path.Value)
+		if err != nil || importPath != path {
+			continue
+		}
+		if spec.Name != nil {
+			return spec.Name.Name
+		}
+		break
+	}
+	return path[strings.LastIndex(path, "/")+1:]
+}
+
+// getComments returns all comments located between "from" and "to" positions.
+func getComments(file *ast.File, from token.Pos, to token.Pos) []*ast.Comment {
+	var comments []*ast.Comment
+	for _, group := range file.Comments {
+		if group.End() <= from || group.Pos() >= to {
+			continue
+		}
+		for _, comment := range group.List {
+			if comment.Pos() > from && comment.End() < to {
+				comments = append(comments, comment)
+			}
+		}
+	}
+	return comments
+}
+
+// parseDocComment parses markers of the doc comment directly above the test func,
+// other lines are the description. Directives like "//go:generate" are skipped.
+func parseDocComment(doc *ast.CommentGroup, grammar *markerGrammar, testMethod *TestMethod) {
+	if doc == nil {
+		return
+	}
+	var lines []string
+	var isPrefixed []bool // lines of comments with the prefix of the grammar
+	for _, comment := range doc.List {
+		if strings.HasPrefix(comment.Text, BCStart) {
+			text, isBlockPrefixed := comment.Text, strings.HasPrefix(comment.Text, grammar.blockStart)
+			if isBlockPrefixed {
+				text = BCStart + text[len(grammar.blockStart):]
+			}
+			blockLines := getBlockLines(text)
+			lines = append(lines, blockLines...)
+			for range blockLines {
+				isPrefixed = append(isPrefixed, isBlockPrefixed)
+			}
+			continue
+		}
+		if grammar.prefix != OLC && strings.HasPrefix(comment.Text, grammar.prefix) {
+			lines = append(lines, strings.TrimSpace(comment.Text[len(grammar.prefix):]))
+			isPrefixed = append(isPrefixed, true)
+			continue
+		}
+		line := comment.Text[len(OLC):]
+		if line != "" && !unicode.IsSpace(rune(line[0])) {
+			continue // directive
+		}
+		lines = append(lines, strings.TrimSpace(line))
+		isPrefixed = append(isPrefixed, grammar.prefix == OLC)
+	}
+
+	var description []string
+	for i, line := range lines {
+		if isPrefixed[i] && grammar.marker.MatchString(" "+line) {
+			parseOneLineComment(grammar.prefix+" "+line, grammar, testMethod)
+			continue
+		}
+		if line != "" || (len(description) > 0 && description[len(description)-1] != "") {
+			description = append(description, line)
+		}
+	}
+	for len(description) > 0 && description[len(description)-1] == "" {
+		description = description[:len(description)-1]
+	}
+	testMethod.description = description
+}
+
+// getFileLevelComments returns comments which are not inside of any func.
+func getFileLevelComments(file *ast.File) []*ast.Comment {
+	var comments []*ast.Comment
+	for _, group := range file.Comments {
+		for _, comment := range group.List {
+			if !isInsideFunc(file, comment.Pos()) {
+				comments = append(comments, comment)
+			}
+		}
+	}
+	return comments
+}
+
+func isInsideFunc(file *ast.File, pos token.Pos) bool {
+	for _, decl := range file.Decls {
+		if funcDecl, ok := decl.(*ast.FuncDecl); ok && funcDecl.Body != nil &&
+			pos > funcDecl.Body.Lbrace && pos < funcDecl.Body.Rbrace {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,306 @@
+package tc2mdc
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestASTMultiLineSignature(t *testing.T) {
+	// > Methods, Go AST
+	// # ParseFile() finds a test func with a multi-line signature and a different name of "*testing.T" param
+	// ## GIVEN Input is
+	var input = strings.Join([]string{
+		// - "package somePackage"
+		"package somePackage",
+		// - "func TestSomething("
+		"func TestSomething(",
+		// - "	tt *testing.T,"
+		"	tt *testing.T,",
+		// - ") {"
+		") {",
+		// - "	// # Scenario"
+		"	// # Scenario",
+		// - "}"
+		"}",
+	}, "\n")
+
+	// ## WHEN ParseFile()
+	testData, err := ParseFile("some_test.go", []byte(input))
+	// ## THEN output data has:
+	require.Nil(t, err, "must be no error")
+	// - "packageName" = 'somePackage'
+	require.Equal(t, "somePackage", testData.packageName)
+	// - "Methods" contains 1 element: "name" = 'TestSomething', "scenario" = 'Scenario'
+	require.Equal(t, 1, len(testData.methods))
+	require.Equal(t, "TestSomething", testData.methods[0].name)
+	require.Equal(t, "Scenario", testData.methods[0].scenario)
+	// -- "file" = 'some_test.go', "line" = 2 - the line of the "func" keyword
+	require.Equal(t, "some_test.go", testData.methods[0].file)
+	require.Equal(t, 2, testData.methods[0].line)
+}
+
+func TestASTSkipsNonTestFuncs(t *testing.T) {
+	// > Methods, Go AST
+	// # ParseFile() skips helpers, methods and funcs with wrong "Test" names or params
+	// ## GIVEN Input contains only not test funcs with markers:
+	var input = strings.Join([]string{
+		"package somePackage",
+		`import "testing"`,
+		// - helper "func helper(t *testing.T)"
+		"func helper(t *testing.T) {",
+		"	// # Helper",
+		"}",
+		// - method "func (s *Suite) TestSomething(t *testing.T)"
+		"func (s *Suite) TestSomething(t *testing.T) {",
+		"	// # Method",
+		"}",
+		// - lower case after prefix "func Testsomething(t *testing.T)"
+		"func Testsomething(t *testing.T) {",
+		"	// # Lower case",
+		"}",
+		// - wrong param "func TestSomething(b *testing.B)"
+		"func TestSomething(b *testing.B) {",
+		"	// # Benchmark param",
+		"}",
+	}, "\n")
+
+	// ## WHEN ParseFile()
+	testData, err := ParseFile("some_test.go", []byte(input))
+	// ## THEN no error, "Methods" are 'nil'
+	require.Nil(t, err, "must be no error")
+	require.Nil(t, testData.methods, "methods must be nil")
+}
+
+func TestASTClosureAtColumnZero(t *testing.T) {
+	// > Comments, Go AST
+	// # ParseFile() keeps markers after a closure ending at column 0 in the same test func
+	// ## GIVEN Input is
+	var input = strings.Join([]string{
+		"package somePackage",
+		// - import "testing" as "tst"
+		`import tst "testing"`,
+		"func TestSomething(t *tst.T) {",
+		"	// ## GIVEN set",
+		// - a closure with "}" at column 0
+		"	check := func() {",
+		"}",
+		"	// ## THEN check",
+		"	check()",
+		"}",
+		// - a comment after the func end
+		"// ## WHEN outside",
+	}, "\n")
+
+	// ## WHEN ParseFile()
+	testData, err := ParseFile("some_test.go", []byte(input))
+	// ## THEN output data has:
+	require.Nil(t, err, "must be no error")
+	// - "Methods" contains 1 element with 2 "steps": GIVEN 'set', THEN 'check'
+	require.Equal(t, 1, len(testData.methods))
+	require.Equal(t, []TestStep{{Given, "set"}, {Then, "check"}}, testData.methods[0].steps)
+}
+
+func TestASTBlockComments(t *testing.T) {
+	// > Comments, Go AST
+	// # ParseFile() parses markers of block comments inside and directly above a test func line by line
+	// ## GIVEN Input is
+	var input = strings.Join([]string{
+		"package somePackage",
+		// - a godoc-style block comment above the func with "# Scenario" and "> Tag"
+		"/*",
+		" * # Scenario",
+		" * > Tag",
+		" */",
+		"func TestSomething(t *testing.T) {",
+		// - a block comment inside of the func with "## GIVEN set" and "- detail"
+		"	/* ## GIVEN set",
+		"	   - detail */",
+		// - a one line comment with "## WHEN act" and a one line block comment with "## THEN check"
+		"	// ## WHEN act",
+		"	/* ## THEN check */",
+		"}",
+	}, "\n")
+
+	// ## WHEN ParseFile()
+	testData, err := ParseFile("some_test.go", []byte(input))
+	// ## THEN output data has 1 method with:
+	require.Nil(t, err, "must be no error")
+	require.Equal(t, 1, len(testData.methods))
+	// - "scenario" = 'Scenario', "tags" = 'Tag'
+	require.Equal(t, "Scenario", testData.methods[0].scenario)
+	require.Equal(t, []string{"Tag"}, testData.methods[0].tags)
+	// - 4 "steps": GIVEN 'set', 'detail', WHEN 'act', THEN 'check'
+	require.Equal(t, []TestStep{
+		{Given, "set"}, {Common, "detail"}, {When, "act"}, {Then, "check"},
+	}, testData.methods[0].steps)
+}
+
+func TestASTDocDescription(t *testing.T) {
+	// > Comments, Go AST
+	// # ParseFile() returns the doc comment above a test func as its description, markers of it are parsed
+	// ## GIVEN Input is
+	var input = strings.Join([]string{
+		"package somePackage",
+		// - a doc comment with 2 paragraphs, a marker and a directive:
+		// -- "// TestSomething checks"
+		"// TestSomething checks",
+		// -- "// the intent."
+		"// the intent.",
+		"//",
+		// -- "// # Scenario"
+		"// # Scenario",
+		// -- "// Second paragraph."
+		"// Second paragraph.",
+		// -- "//go:noinline"
+		"//go:noinline",
+		"func TestSomething(t *testing.T) {",
+		"}",
+	}, "\n")
+
+	// ## WHEN ParseFile()
+	testData, err := ParseFile("some_test.go", []byte(input))
+	// ## THEN output data has 1 method with:
+	require.Nil(t, err, "must be no error")
+	require.Equal(t, 1, len(testData.methods))
+	// - "scenario" = 'Scenario'
+	require.Equal(t, "Scenario", testData.methods[0].scenario)
+	// - "description" = 'TestSomething checks', 'the intent.', '', 'Second paragraph.'
+	require.Equal(t, []string{"TestSomething checks", "the intent.", "", "Second paragraph."}, testData.methods[0].description)
+}
+
+func TestASTSubtests(t *testing.T) {
+	// > Subtests, Go AST
+	// # ParseFile() returns "t.Run()" calls with a name literal as nested subtests with their own markers
+	// ## GIVEN Input is
+	var input = strings.Join([]string{
+		"package somePackage",
+		"func TestParent(t *testing.T) {",
+		"	// # Parent scenario",
+		// - a subtest 'when X' with a nested subtest 'then Y'
+		`	t.Run("when X", func(t *testing.T) {`,
+		"		// > Tag",
+		"		// ## WHEN X",
+		`		t.Run("then Y", func(t *testing.T) {`,
+		"			// ## THEN Y",
+		"		})",
+		"	})",
+		// - a parent marker after the subtest
+		"	// - parent step",
+		// - a call with not a literal name is not a subtest
+		"	t.Run(name, func(t *testing.T) {",
+		"		// - dynamic step",
+		"	})",
+		"}",
+	}, "\n")
+
+	// ## WHEN ParseFile()
+	testData, err := ParseFile("some_test.go", []byte(input))
+	// ## THEN output data has 1 method:
+	require.Nil(t, err, "must be no error")
+	require.Equal(t, 1, len(testData.methods))
+	// - 'TestParent' with "scenario" and 2 steps: 'parent step', 'dynamic step'
+	parent := testData.methods[0]
+	require.Equal(t, "Parent scenario", parent.scenario)
+	require.Equal(t, []TestStep{{Common, "parent step"}, {Common, "dynamic step"}}, parent.steps)
+	// -- 1 subtest: 'TestParent/when_X' at line 4 with "tags" = 'Tag' and 1 step WHEN 'X'
+	require.Equal(t, 1, len(parent.subtests))
+	require.Equal(t, "TestParent/when_X", parent.subtests[0].name)
+	require.Equal(t, 4, parent.subtests[0].line)
+	require.Equal(t, []string{"Tag"}, parent.subtests[0].tags)
+	require.Equal(t, []TestStep{{When, "X"}}, parent.subtests[0].steps)
+	// --- 1 subtest: 'TestParent/when_X/then_Y' with 1 step THEN 'Y'
+	require.Equal(t, []TestMethod{{name: "TestParent/when_X/then_Y", file: "some_test.go", line: 7,
+		steps: []TestStep{{Then, "Y"}}}}, parent.subtests[0].subtests)
+}
+
+func TestASTSyntaxError(t *testing.T) {
+	// > Errors, Go AST
+	// # ParseFile() returns error on input which is not a Go file
+	// ## GIVEN Input is " something"
+	var input = " something"
+	// ## WHEN ParseFile()
+	testData, err := ParseFile("some_test.go", []byte(input))
+	// ## THEN error with the file name, data is 'nil'
+	require.Nil(t, testData, "data must be nil")
+	require.ErrorContains(t, err, "some_test.go:1:2")
+}
+
+func TestASTFuncKinds(t *testing.T) {
+	// > Methods, Go AST
+	// # ParseFile() finds benchmarks, fuzz tests and examples with the output of an example
+	// ## GIVEN Input is
+	var input = strings.Join([]string{
+		"package somePackage",
+		`import "testing"`,
+		// - "func BenchmarkSum(b *testing.B)" with a scenario
+		"func BenchmarkSum(b *testing.B) {",
+		"	// # Sum of numbers",
+		"}",
+		// - "func FuzzParse(f *testing.F)"
+		"func FuzzParse(f *testing.F) {}",
+		// - "func ExampleSum()" with a step and the "// Output:" comment with a bullet-like line
+		"func ExampleSum() {",
+		"	// ## WHEN print",
+		"	fmt.Println(3)",
+		"	// Output: 3",
+		"	// - not a step",
+		"}",
+		// - not test funcs "func BenchmarkSum(t *testing.T)" and "func Example(s string)"
+		"func BenchmarkSum(t *testing.T) {}",
+		"func Example(s string) {}",
+	}, "\n")
+
+	// ## WHEN ParseFile()
+	testData, err := ParseFile("some_test.go", []byte(input))
+	// ## THEN methods are of kinds benchmark, fuzz and example
+	require.Nil(t, err, "must be no error")
+	var kinds []string
+	for _, method := range testData.Methods() {
+		kinds = append(kinds, method.Name()+":"+method.Kind().String())
+	}
+	require.Equal(t, []string{"BenchmarkSum:benchmark", "FuzzParse:fuzz", "ExampleSum:example"}, kinds)
+	require.Equal(t, "Sum of numbers", testData.methods[0].scenario)
+	// ## AND the example has the step and the output lines which are not markers
+	require.Equal(t, []TestStep{{When, "print"}}, testData.methods[2].steps)
+	require.Equal(t, []string{"3", "- not a step"}, testData.methods[2].Output())
+}
+
+func TestASTBackground(t *testing.T) {
+	// > Background, Go AST
+	// # ParseFile() parses markers of "TestMain" and "// @background" funcs as the background of all tests
+	// ## GIVEN Input is
+	var input = strings.Join([]string{
+		"package somePackage",
+		`import "testing"`,
+		// - "func TestMain(m *testing.M)" with a step
+		"func TestMain(m *testing.M) {",
+		"	// ## GIVEN started server",
+		"	os.Exit(m.Run())",
+		"}",
+		// - a helper with the "// @background" annotation and a bullet step
+		"// @background",
+		"func setupDB() {",
+		"	// ## GIVEN connected DB",
+		"	// - empty tables",
+		"}",
+		// - a helper without the annotation
+		"func helper() {",
+		"	// ## GIVEN skipped",
+		"}",
+		"func TestA(t *testing.T) {",
+		"	// ## WHEN act",
+		"}",
+	}, "\n")
+
+	// ## WHEN ParseFile()
+	testData, err := ParseFile("some_test.go", []byte(input))
+	// ## THEN the background has steps of "TestMain" and "setupDB" in the order of the source
+	require.Nil(t, err, "must be no error")
+	require.Equal(t, []TestStep{{Given, "started server"}, {Given, "connected DB"}, {Common, "empty tables"}},
+		testData.Background())
+	// ## AND the only method is 'TestA'
+	require.Equal(t, 1, len(testData.methods))
+	require.Equal(t, "TestA", testData.methods[0].name)
+}
@@ -0,0 +1,80 @@
+package tc2mdc
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// Coverage is the count of test funcs of a package and of documented ones: with a scenario and a step.
+type Coverage struct {
+	Package    string  `json:"package"`
+	Tests      int     `json:"tests"`
+	Documented int     `json:"documented"`
+	Percent    float64 `json:"percent"` // 100 if there are no tests
+}
+
+// CoverageReport is the coverage of each package and of all of them.
+type CoverageReport struct {
+	Packages []Coverage `json:"packages"`
+	Total    Coverage   `json:"total"`
+}
+
+// GetCoverage returns the coverage of test funcs (without subtests, benchmarks, fuzz tests and examples)
+// by documentation per package, 'nil' items are skipped.
+func GetCoverage(packages []*TestData) CoverageReport {
+	report := CoverageReport{Packages: []Coverage{}, Total: Coverage{Package: "Total"}}
+	for _, data := range packages {
+		if data == nil {
+			continue
+		}
+		coverage := Coverage{Package: data.packageName}
+		for _, method := range data.methods {
+			if method.kind != TestFunc {
+				continue
+			}
+			coverage.Tests++
+			if isDocumented(method) {
+				coverage.Documented++
+			}
+		}
+		coverage.Percent = getPercent(coverage.Documented, coverage.Tests)
+		report.Packages = append(report.Packages, coverage)
+		report.Total.Tests += coverage.Tests
+		report.Total.Documented += coverage.Documented
+	}
+	report.Total.Percent = getPercent(report.Total.Documented, report.Total.Tests)
+	return report
+}
+
+// isDocumented checks the method has a scenario and at least one step.
+func isDocumented(method TestMethod) bool {
+	return method.scenario != "" && len(method.steps) != 0
+}
+
+func getPercent(count int, total int) float64 {
+	if total == 0 {
+		return 100
+	}
+	return float64(count) * 100 / float64(total)
+}
+
+// Markdown returns the report as a MD table with a row per package and the total one.
+func (report CoverageReport) Markdown() []string {
+	mdText := []string{"## Coverage", "| Package | Tests | Documented | Coverage |", "|---|---:|---:|---:|"}
+	for _, coverage := range report.Packages {
+		mdText = append(mdText, getCoverageRow("`"+coverage.Package+"`", coverage))
+	}
+	return append(mdText, getCoverageRow("**"+report.Total.Package+"**", report.Total))
+}
+
+// JSON returns the report as indented JSON, the report is always marshalled without errors.
+func (report CoverageReport) JSON() []string {
+	text, _ := json.MarshalIndent(report, "", "  ")
+	return strings.Split(string(text), "\n")
+}
+
+func getCoverageRow(caption string, coverage Coverage) string {
+	return "| " + caption + " | " + strconv.Itoa(coverage.Tests) + " | " + strconv.Itoa(coverage.Documented) +
+		" | " + strconv.FormatFloat(coverage.Percent, 'f', 1, 64) + "% |"
+}
@@ -0,0 +1,68 @@
+package tc2mdc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoverage(t *testing.T) {
+	// > Coverage
+	// # GetCoverage() counts test funcs with a scenario and a step per package and in total
+	// ## GIVEN - package 'p1' with 3 methods:
+	var p1 = &TestData{packageName: "p1", methods: []TestMethod{
+		// - 'TestA' with scenario and step, 'TestB' with scenario only, 'TestC' with step only
+		{name: "TestA", scenario: "A", steps: []TestStep{{When, "act"}}},
+		{name: "TestB", scenario: "B"},
+		{name: "TestC", steps: []TestStep{{Common, "step"}}},
+	}}
+	// - package 'p2' without methods
+	var p2 = &TestData{packageName: "p2"}
+
+	// ## WHEN GetCoverage()
+	report := GetCoverage([]*TestData{p1, nil, p2})
+
+	// ## THEN report has:
+	require.Equal(t, CoverageReport{
+		Packages: []Coverage{
+			// - 'p1' with 1 of 3 documented tests
+			{Package: "p1", Tests: 3, Documented: 1, Percent: 100.0 / 3},
+			// - 'p2' with 100% without tests
+			{Package: "p2", Tests: 0, Documented: 0, Percent: 100},
+		},
+		// - total of 1 of 3 documented tests
+		Total: Coverage{Package: "Total", Tests: 3, Documented: 1, Percent: 100.0 / 3},
+	}, report)
+
+	// ## WHEN Markdown()
+	// ## THEN MD text is a table:
+	require.Equal(t, []string{
+		"## Coverage",
+		"| Package | Tests | Documented | Coverage |",
+		"|---|---:|---:|---:|",
+		"| `p1` | 3 | 1 | 33.3% |",
+		"| `p2` | 0 | 0 | 100.0% |",
+		"| **Total** | 3 | 1 | 33.3% |",
+	}, report.Markdown())
+
+	// ## WHEN JSON() of the report of 'p2'
+	// ## THEN JSON text is:
+	require.Equal(t, []string{
+		"{",
+		`  "packages": [`,
+		"    {",
+		`      "package": "p2",`,
+		`      "tests": 0,`,
+		`      "documented": 0,`,
+		`      "percent": 100`,
+		"    }",
+		"  ],",
+		`  "total": {`,
+		`    "package": "Total",`,
+		`    "tests": 0,`,
+		`    "documented": 0,`,
+		`    "percent": 100`,
+		"  }",
+		"}",
+	}, GetCoverage([]*TestData{p2}).JSON())
+}
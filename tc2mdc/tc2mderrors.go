@@ -0,0 +1,172 @@
+package tc2mdc
+
+import (
+	"errors"
+	"go/ast"
+	"go/scanner"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ParseError is a problem of a test file: a syntax error or a malformed marker which is skipped.
+type ParseError struct {
+	File   string // empty if the input has no file name
+	Line   int    // 1-based, 0 if the problem is not of a line
+	Column int    // 1-based in bytes, 0 if it is unknown
+	Marker string // the malformed marker, empty if the problem is not of a marker
+	Reason string
+}
+
+// Error returns the problem as "file:line:column: reason" without unknown parts of the position.
+func (err *ParseError) Error() string {
+	var position []string
+	if err.File != "" {
+		position = append(position, err.File)
+	}
+	if err.Line > 0 {
+		position = append(position, strconv.Itoa(err.Line))
+		if err.Column > 0 {
+			position = append(position, strconv.Itoa(err.Column))
+		}
+	}
+	if position == nil {
+		return err.Reason
+	}
+	return strings.Join(position, ":") + ": " + err.Reason
+}
+
+// ParseErrors are all problems of a test file ordered by position, each of them is found by errors.As().
+type ParseErrors []*ParseError
+
+// Error returns the first problem and the count of other ones.
+func (errs ParseErrors) Error() string {
+	switch len(errs) {
+	case 0:
+		{
+			return "no errors"
+		}
+	case 1:
+		{
+			return errs[0].Error()
+		}
+	}
+	return errs[0].Error() + " (and " + strconv.Itoa(len(errs)-1) + " more errors)"
+}
+
+// Unwrap returns all problems for errors.Is() and errors.As().
+func (errs ParseErrors) Unwrap() []error {
+	var list []error
+	for _, err := range errs {
+		list = append(list, err)
+	}
+	return list
+}
+
+// err returns 'nil' if there are no problems, otherwise problems sorted by position.
+func (errs ParseErrors) err() error {
+	if len(errs) == 0 {
+		return nil
+	}
+	sort.SliceStable(errs, func(i, j int) bool {
+		if errs[i].Line != errs[j].Line {
+			return errs[i].Line < errs[j].Line
+		}
+		return errs[i].Column < errs[j].Column
+	})
+	return errs
+}
+
+// getSyntaxErrors returns errors of go/parser as ParseErrors.
+func getSyntaxErrors(filename string, err error) error {
+	var list scanner.ErrorList
+	if !errors.As(err, &list) {
+		return ParseErrors{{File: filename, Reason: err.Error()}}
+	}
+	var errs ParseErrors
+	for _, syntaxError := range list {
+		errs = append(errs, &ParseError{File: filename, Line: syntaxError.Pos.Line, Column: syntaxError.Pos.Column,
+			Reason: syntaxError.Msg})
+	}
+	return errs.err()
+}
+
+// appendMarkerErrors adds problems of markers of the comment with the prefix of the grammar, each line
+// of a block comment is checked. Lines of a doc comment are trimmed by the parser, so extra spaces before
+// a marker are allowed there.
+func appendMarkerErrors(comment *ast.Comment, isDoc bool, filename string, fset *token.FileSet, grammar *markerGrammar,
+	errs *ParseErrors) {
+	position := fset.Position(comment.Pos())
+	if !strings.HasPrefix(comment.Text, BCStart) {
+		if !strings.HasPrefix(comment.Text, grammar.prefix) {
+			return
+		}
+		text := comment.Text[len(grammar.prefix):]
+		if marker, offset, reason := getMarkerError(text, grammar, !isDoc); reason != "" {
+			*errs = append(*errs, &ParseError{filename, position.Line, position.Column + len(grammar.prefix) + offset, marker, reason})
+		}
+		return
+	}
+	if !strings.HasPrefix(comment.Text, grammar.blockStart) {
+		return
+	}
+	for i, line := range strings.Split(comment.Text, "\n") {
+		column := 1
+		if i == 0 {
+			line = strings.TrimPrefix(line, grammar.blockStart)
+			column = position.Column + len(grammar.blockStart)
+		}
+		text := strings.TrimLeft(line, " \t*")
+		column += len(line) - len(text)
+		if marker, offset, reason := getMarkerError(" "+strings.TrimSuffix(text, BCEnd), grammar, false); reason != "" {
+			*errs = append(*errs, &ParseError{filename, position.Line + i, column + offset - 1, marker, reason})
+		}
+	}
+}
+
+// getMarkerError returns the problem of a comment text (without "//") which looks like a marker but is not
+// parsed as one, the empty reason if there is no problem. The offset is of the marker in the text.
+// A marker followed by a word is a near-miss only if the word is a step keyword, e.g. "##THEN", so
+// comments like "--verbose" or "#nosec" are not markers.
+func getMarkerError(text string, grammar *markerGrammar, checkExtraSpace bool) (marker string, offset int, reason string) {
+	trimmed := strings.TrimLeft(text, " \t")
+	offset = len(text) - len(trimmed)
+	marker = trimmed[:len(trimmed)-len(strings.TrimLeft(trimmed, grammar.chars))]
+	if marker == "" || grammar.title.MatchString(" "+trimmed) {
+		return "", 0, ""
+	}
+	rest := trimmed[len(marker):]
+	hasText := strings.TrimSpace(rest) != ""
+	isKnown := containsString(grammar.tokens, marker)
+
+	switch {
+	case !isKnown:
+		{
+			if strings.Count(marker, marker[:1]) == len(marker) && len(marker) <= 4 &&
+				hasText && unicode.IsSpace(rune(rest[0])) {
+				return marker, offset, "unknown marker \"" + marker + "\""
+			}
+		}
+	case rest != "" && !unicode.IsSpace(rune(rest[0])):
+		{
+			if getGWTStep(rest).kind != GWT {
+				return marker, offset, "no space after marker \"" + marker + "\""
+			}
+		}
+	case !hasText:
+		{
+			return marker, offset, "marker \"" + marker + "\" without text"
+		}
+	case offset == 0:
+		{
+			return marker, offset, "no space between \"" + grammar.prefix + "\" and marker \"" + marker + "\""
+		}
+	case checkExtraSpace && offset > 1:
+		{
+			return marker, offset, "extra space before marker \"" + marker + "\""
+		}
+	}
+	return "", 0, ""
+}
@@ -0,0 +1,109 @@
+package tc2mdc
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseErrorsOfMarkers(t *testing.T) {
+	// > Errors
+	// # ParseFile() returns the data and all malformed markers as ParseErrors
+	// ## GIVEN Input is
+	var input = strings.Join([]string{
+		"package somePackage",
+		"",
+		"import \"testing\"",
+		"",
+		// - "func TestA(t *testing.T)" with "// # Scenario" and malformed markers
+		"func TestA(t *testing.T) {",
+		"	// # Scenario",
+		"	//## WHEN act",
+		"	// ### THEN check",
+		"	/* ##THEN check */",
+		"}",
+	}, "\n")
+
+	// ## WHEN ParseFile()
+	testData, err := ParseFile("some_test.go", []byte(input))
+
+	// ## THEN data is parsed without malformed markers
+	require.NotNil(t, testData, "data must be returned")
+	require.Equal(t, "Scenario", testData.methods[0].scenario)
+	require.Nil(t, testData.methods[0].steps)
+	// - error is ParseErrors with 3 errors:
+	var errs ParseErrors
+	require.True(t, errors.As(err, &errs), "must be ParseErrors")
+	require.Equal(t, ParseErrors{
+		// -- {7, 4, '##', no space between}
+		{File: "some_test.go", Line: 7, Column: 4, Marker: "##", Reason: `no space between "//" and marker "##"`},
+		// -- {8, 5, '###', unknown}
+		{File: "some_test.go", Line: 8, Column: 5, Marker: "###", Reason: `unknown marker "###"`},
+		// -- {9, 5, '##', no space after}
+		{File: "some_test.go", Line: 9, Column: 5, Marker: "##", Reason: `no space after marker "##"`},
+	}, errs)
+	// - error text is the first error and the count of others
+	require.EqualError(t, err, `some_test.go:7:4: no space between "//" and marker "##" (and 2 more errors)`)
+	// - the first error is found by errors.As() as *ParseError
+	var parseError *ParseError
+	require.True(t, errors.As(err, &parseError), "must be ParseError")
+	require.Equal(t, 7, parseError.Line)
+}
+
+func TestParseErrorsOfSyntax(t *testing.T) {
+	// > Errors
+	// # ParseFile() returns all syntax errors with positions and no data
+	// ## WHEN ParseFile() of a func with 2 syntax errors
+	testData, err := ParseFile("some_test.go", []byte("package somePackage\nfunc A() {\n\tx :=\n}\nfunc B( {}\n"))
+
+	// ## THEN there is no data
+	require.Nil(t, testData, "data must be nil")
+	// - error is ParseErrors with at least 2 errors at lines 4 and 5
+	var errs ParseErrors
+	require.True(t, errors.As(err, &errs), "must be ParseErrors")
+	require.GreaterOrEqual(t, len(errs), 2)
+	require.Equal(t, 4, errs[0].Line)
+	require.Equal(t, 5, errs[len(errs)-1].Line)
+	require.Equal(t, "", errs[0].Marker)
+
+	// ## WHEN Parse() of nil input
+	_, err = Parse(nil)
+	// ## THEN error is ParseErrors with one ParseError without a position
+	require.True(t, errors.As(err, &errs), "must be ParseErrors")
+	var parseError *ParseError
+	require.True(t, errors.As(err, &parseError), "must be ParseError")
+	require.EqualError(t, err, "nil input")
+}
+
+func TestParseErrorsOfSyntaxWithLines(t *testing.T) {
+	// > Errors
+	// # Parse() of a file with "package" and syntax errors returns the data of lines with the errors
+	// ## GIVEN Input is a file with a test and a broken line
+	var input = []string{
+		"package a",
+		"",
+		"func TestA(t *testing.T) {",
+		"	// ## WHEN act",
+		"	x := ",
+		"}",
+	}
+
+	// ## WHEN Parse()
+	testData, err := Parse(input)
+
+	// ## THEN the test is parsed by lines
+	require.NotNil(t, testData, "data must be returned")
+	require.Equal(t, "TestA", testData.methods[0].name)
+	// - error is ParseErrors with the syntax error at line 6
+	var errs ParseErrors
+	require.True(t, errors.As(err, &errs), "must be ParseErrors")
+	require.Equal(t, 6, errs[0].Line)
+
+	// ## WHEN Parse() of the snippet without "package"
+	testData, err = Parse(input[2:])
+	// ## THEN the test is parsed by lines without errors
+	require.Nil(t, err, "must be no error")
+	require.Equal(t, "TestA", testData.methods[0].name)
+}
@@ -0,0 +1,189 @@
+package tc2mdc
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SchemaVersion is the version of the export schema, it is increased on incompatible changes of it.
+const SchemaVersion = 1
+
+// ExportDocument is the root of the export schema: test data of all packages.
+type ExportDocument struct {
+	SchemaVersion int             `json:"schemaVersion" yaml:"schemaVersion"`
+	Packages      []ExportPackage `json:"packages" yaml:"packages"`
+}
+
+// ExportPackage is the test data of a package (or a test file).
+type ExportPackage struct {
+	Title      string        `json:"title,omitempty" yaml:"title,omitempty"`
+	Package    string        `json:"package" yaml:"package"`
+	Tests      []ExportTest  `json:"tests" yaml:"tests"`
+	Suites     []ExportSuite `json:"suites,omitempty" yaml:"suites,omitempty"`
+	Background []ExportStep  `json:"background,omitempty" yaml:"background,omitempty"` // steps shared by all tests
+}
+
+// ExportSuite is a testify suite with the background steps of its setup methods.
+type ExportSuite struct {
+	Name       string       `json:"name" yaml:"name"`
+	Background []ExportStep `json:"background,omitempty" yaml:"background,omitempty"`
+}
+
+// ExportTest is a test method or a subtest, "kind" is the name of FuncKind, e.g. 'benchmark'.
+type ExportTest struct {
+	Name        string        `json:"name" yaml:"name"`
+	Kind        string        `json:"kind" yaml:"kind"`
+	Suite       string        `json:"suite,omitempty" yaml:"suite,omitempty"`
+	File        string        `json:"file,omitempty" yaml:"file,omitempty"`
+	Line        int           `json:"line,omitempty" yaml:"line,omitempty"`
+	SourceLink  string        `json:"sourceLink,omitempty" yaml:"sourceLink,omitempty"`
+	Tags        []string      `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Scenario    string        `json:"scenario,omitempty" yaml:"scenario,omitempty"`
+	Description []string      `json:"description,omitempty" yaml:"description,omitempty"`
+	Steps       []ExportStep  `json:"steps,omitempty" yaml:"steps,omitempty"`
+	Cases       []ExportCase  `json:"cases,omitempty" yaml:"cases,omitempty"`
+	Output      []string      `json:"output,omitempty" yaml:"output,omitempty"` // expected output of an example
+	Result      *ExportResult `json:"result,omitempty" yaml:"result,omitempty"`
+	Subtests    []ExportTest  `json:"subtests,omitempty" yaml:"subtests,omitempty"`
+}
+
+// ExportStep is a step of a test, "kind" is the name of StepKind, e.g. 'gwt'.
+type ExportStep struct {
+	Kind string `json:"kind" yaml:"kind"`
+	Text string `json:"text" yaml:"text"`
+}
+
+// ExportCase is a case of a table-driven test.
+type ExportCase struct {
+	Name   string        `json:"name" yaml:"name"`
+	Fields []ExportField `json:"fields,omitempty" yaml:"fields,omitempty"`
+}
+
+// ExportField is a field of a case with its source code as the value.
+type ExportField struct {
+	Name  string `json:"name" yaml:"name"`
+	Value string `json:"value" yaml:"value"`
+}
+
+// ExportResult is the result of a test from "go test -json" output.
+type ExportResult struct {
+	Action  string   `json:"action" yaml:"action"`
+	Elapsed float64  `json:"elapsed" yaml:"elapsed"`
+	Output  []string `json:"output,omitempty" yaml:"output,omitempty"`
+}
+
+// Export returns the test data in the export schema, 'nil' items are skipped.
+func Export(data []*TestData) *ExportDocument {
+	document := &ExportDocument{SchemaVersion: SchemaVersion, Packages: []ExportPackage{}}
+	for _, packageData := range data {
+		if packageData == nil {
+			continue
+		}
+		exportPackage := ExportPackage{Title: packageData.title, Package: packageData.packageName, Tests: []ExportTest{},
+			Background: getExportSteps(packageData.background)}
+		for _, method := range packageData.methods {
+			exportPackage.Tests = append(exportPackage.Tests, getExportTest(method, packageData.toc[method.name].gitLink))
+		}
+		for _, suite := range packageData.suites {
+			exportSuite := ExportSuite{Name: suite.name, Background: getExportSteps(suite.background)}
+			exportPackage.Suites = append(exportPackage.Suites, exportSuite)
+		}
+		document.Packages = append(document.Packages, exportPackage)
+	}
+	return document
+}
+
+func getExportSteps(steps []TestStep) []ExportStep {
+	var exportSteps []ExportStep
+	for _, step := range steps {
+		exportSteps = append(exportSteps, ExportStep{Kind: step.kind.String(), Text: step.comment})
+	}
+	return exportSteps
+}
+
+func getExportTest(method TestMethod, gitLink string) ExportTest {
+	test := ExportTest{
+		Name:        method.name,
+		Kind:        method.kind.String(),
+		Suite:       method.suite,
+		File:        method.file,
+		Line:        method.line,
+		SourceLink:  gitLink,
+		Tags:        method.tags,
+		Scenario:    method.scenario,
+		Description: method.description,
+		Output:      method.output,
+	}
+	test.Steps = getExportSteps(method.steps)
+	for _, testCase := range method.cases {
+		exportCase := ExportCase{Name: testCase.name}
+		for _, field := range testCase.fields {
+			exportCase.Fields = append(exportCase.Fields, ExportField{Name: field.name, Value: field.value})
+		}
+		test.Cases = append(test.Cases, exportCase)
+	}
+	if method.result.action != "" {
+		test.Result = &ExportResult{Action: method.result.action, Elapsed: method.result.elapsed, Output: method.result.output}
+	}
+	for _, subtest := range method.subtests {
+		test.Subtests = append(test.Subtests, getExportTest(subtest, ""))
+	}
+	return test
+}
+
+// jsonWriter writes test data in the export schema as indented JSON.
+type jsonWriter struct{}
+
+func (writer jsonWriter) Write(data *TestData) []string {
+	if data == nil {
+		return nil
+	}
+	return writer.WriteAll([]*TestData{data})
+}
+
+// WriteAll returns one JSON document, the schema types are always marshalled without errors.
+func (writer jsonWriter) WriteAll(data []*TestData) []string {
+	text, _ := json.MarshalIndent(Export(data), "", "  ")
+	return strings.Split(string(text), "\n")
+}
+
+// WriteIndex returns 'nil', all packages are exported by WriteAll().
+func (writer jsonWriter) WriteIndex(packages []*TestData, links []string) []string {
+	return nil
+}
+
+func (writer jsonWriter) Extension() string {
+	return ".json"
+}
+
+// yamlWriter writes test data in the export schema as YAML.
+type yamlWriter struct{}
+
+func (writer yamlWriter) Write(data *TestData) []string {
+	if data == nil {
+		return nil
+	}
+	return writer.WriteAll([]*TestData{data})
+}
+
+// WriteAll returns one YAML document, the schema types are always marshalled without errors.
+func (writer yamlWriter) WriteAll(data []*TestData) []string {
+	var buffer bytes.Buffer
+	encoder := yaml.NewEncoder(&buffer)
+	encoder.SetIndent(2)
+	_ = encoder.Encode(Export(data))
+	_ = encoder.Close()
+	return strings.Split(strings.TrimSuffix(buffer.String(), "\n"), "\n")
+}
+
+// WriteIndex returns 'nil', all packages are exported by WriteAll().
+func (writer yamlWriter) WriteIndex(packages []*TestData, links []string) []string {
+	return nil
+}
+
+func (writer yamlWriter) Extension() string {
+	return ".yaml"
+}
@@ -0,0 +1,107 @@
+package tc2mdc
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestExport(t *testing.T) {
+	// > Export
+	// # Export() returns all test data in the versioned schema
+	// ## GIVEN - testData: "title" = 'Title', "packageName" = 'pkg' with 1 method 'TestA':
+	var testData = &TestData{title: "Title", packageName: "pkg"}
+	testData.methods = []TestMethod{{
+		// - "file" = 'a_test.go', "line" = 3, "tags" = 'T1', "scenario" = 'Scenario', "description" = 'Desc'
+		name: "TestA", file: "a_test.go", line: 3, tags: []string{"T1"}, scenario: "Scenario", description: []string{"Desc"},
+		// - "steps": 'GWT' 'WHEN act', indented 'Step'
+		steps: []TestStep{{GWT, "WHEN act"}, {Indented, "Step"}},
+		// - "cases": 'c1' with field {'in', '1'}
+		cases: []TestCase{{name: "c1", fields: []TestField{{"in", "1"}}}},
+		// - failed with output 'boom'
+		result: TestResult{action: ActionFail, elapsed: 0.5, output: []string{"boom"}},
+		// - 1 subtest 'TestA/child'
+		subtests: []TestMethod{{name: "TestA/child"}},
+	}}
+	// - TOC line of 'TestA' with "gitLink" = 'https://x/a_test.go#L3'
+	testData.toc = map[string]TOCLine{"TestA": {gitLink: "https://x/a_test.go#L3"}}
+
+	// ## WHEN Export()
+	document := Export([]*TestData{testData, nil})
+
+	// ## THEN document is:
+	require.Equal(t, &ExportDocument{
+		SchemaVersion: SchemaVersion,
+		Packages: []ExportPackage{{
+			Title:   "Title",
+			Package: "pkg",
+			Tests: []ExportTest{{
+				Name:        "TestA",
+				Kind:        "test",
+				File:        "a_test.go",
+				Line:        3,
+				SourceLink:  "https://x/a_test.go#L3",
+				Tags:        []string{"T1"},
+				Scenario:    "Scenario",
+				Description: []string{"Desc"},
+				Steps:       []ExportStep{{Kind: "gwt", Text: "WHEN act"}, {Kind: "indented", Text: "Step"}},
+				Cases:       []ExportCase{{Name: "c1", Fields: []ExportField{{Name: "in", Value: "1"}}}},
+				Result:      &ExportResult{Action: ActionFail, Elapsed: 0.5, Output: []string{"boom"}},
+				Subtests:    []ExportTest{{Name: "TestA/child", Kind: "test"}},
+			}},
+		}},
+	}, document)
+}
+
+func TestExportJSON(t *testing.T) {
+	// > Export
+	// # JSON and YAML writers return the exported document
+	// ## GIVEN - testData: "packageName" = 'pkg' with 1 method 'TestA' with scenario 'Scenario'
+	var testData = &TestData{packageName: "pkg", methods: []TestMethod{{name: "TestA", scenario: "Scenario"}}}
+	expected := Export([]*TestData{testData})
+
+	// ## WHEN Write() in JSON
+	text := writers["json"].Write(testData)
+	// ## THEN text is the indented JSON of the document
+	require.Equal(t, []string{
+		"{",
+		`  "schemaVersion": 1,`,
+		`  "packages": [`,
+		"    {",
+		`      "package": "pkg",`,
+		`      "tests": [`,
+		"        {",
+		`          "name": "TestA",`,
+		`          "kind": "test",`,
+		`          "scenario": "Scenario"`,
+		"        }",
+		"      ]",
+		"    }",
+		"  ]",
+		"}",
+	}, text)
+	var fromJSON ExportDocument
+	require.Nil(t, json.Unmarshal([]byte(strings.Join(text, "\n")), &fromJSON), "must be no error")
+	require.Equal(t, expected, &fromJSON)
+	require.Equal(t, ".json", writers["json"].Extension())
+
+	// ## WHEN Write() in YAML
+	text = writers["yaml"].Write(testData)
+	// ## THEN text is the YAML of the document
+	require.Equal(t, []string{
+		"schemaVersion: 1",
+		"packages:",
+		"  - package: pkg",
+		"    tests:",
+		"      - name: TestA",
+		"        kind: test",
+		"        scenario: Scenario",
+	}, text)
+	var fromYAML ExportDocument
+	require.Nil(t, yaml.Unmarshal([]byte(strings.Join(text, "\n")), &fromYAML), "must be no error")
+	require.Equal(t, expected, &fromYAML)
+	require.Equal(t, ".yaml", writers["yaml"].Extension())
+}
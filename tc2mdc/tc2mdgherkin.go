@@ -0,0 +1,246 @@
+package tc2mdc
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Default feature name of several packages without a title
+const gherkinFeature = "Test scenarios"
+
+// Gherkin keywords by step kind
+var gherkinKeywords = map[StepKind]string{
+	Given: "Given",
+	When:  "When",
+	Then:  "Then",
+	And:   "And",
+	But:   "But",
+}
+
+// gherkinWriter writes test data as a Gherkin feature: a test method is a scenario, 'GWT' steps are
+// Given/When/Then steps and indented steps are doc strings of the previous step.
+type gherkinWriter struct{}
+
+func (writer gherkinWriter) Write(data *TestData) []string {
+	if data == nil {
+		return nil
+	}
+	return writer.WriteAll([]*TestData{data})
+}
+
+// WriteAll returns one feature, several packages are rules of it. Gherkin rules are not nested,
+// so a suite of one of several packages is a rule after the rule of its package.
+func (writer gherkinWriter) WriteAll(data []*TestData) []string {
+	var packages []*TestData
+	for _, packageData := range data {
+		if packageData != nil {
+			packages = append(packages, packageData)
+		}
+	}
+	if len(packages) == 0 {
+		return nil
+	}
+
+	var text []string
+	if len(packages) == 1 {
+		text = append(text, "Feature: "+getGherkinFeatureName(packages[0]))
+		appendGherkinScenarios(packages[0], "  ", &text)
+		return text
+	}
+
+	name := gherkinFeature
+	for _, packageData := range packages {
+		if packageData.title != "" {
+			name = packageData.title
+			break
+		}
+	}
+	text = append(text, "Feature: "+name)
+	for _, packageData := range packages {
+		packageName := getGherkinFeatureName(packageData)
+		text = append(text, "", "  Rule: "+packageName)
+		appendGherkinBackground(packageData.background, "    ", &text)
+		appendGherkinMethods(packageData, "", "    ", &text)
+		appendGherkinSuites(packageData, packageName+"/", packageData.background, "  ", &text)
+	}
+	return text
+}
+
+// WriteIndex returns 'nil', there is no index of features.
+func (writer gherkinWriter) WriteIndex(packages []*TestData, links []string) []string {
+	return nil
+}
+
+func (writer gherkinWriter) Extension() string {
+	return ".feature"
+}
+
+func getGherkinFeatureName(data *TestData) string {
+	if data.title != "" {
+		return data.title
+	}
+	return data.packageName
+}
+
+// appendGherkinScenarios adds the background of the package, its test funcs as scenarios and its suites as rules.
+func appendGherkinScenarios(data *TestData, indent string, text *[]string) {
+	appendGherkinBackground(data.background, indent, text)
+	appendGherkinMethods(data, "", indent, text)
+	appendGherkinSuites(data, "", nil, indent, text)
+}
+
+// appendGherkinSuites adds each suite as a rule named with the prefix, its background is the background
+// of the parent (if it does not apply to the rule otherwise) with the steps of the suite setup.
+func appendGherkinSuites(data *TestData, prefix string, parentBackground []TestStep, indent string, text *[]string) {
+	for _, suite := range data.suites {
+		*text = append(*text, "", indent+"Rule: "+prefix+suite.name)
+		appendGherkinBackground(append(append([]TestStep(nil), parentBackground...), suite.background...),
+			indent+"  ", text)
+		appendGherkinMethods(data, suite.name, indent+"  ", text)
+	}
+}
+
+// appendGherkinBackground adds the steps as a background, nothing if there are no steps.
+func appendGherkinBackground(steps []TestStep, indent string, text *[]string) {
+	if len(steps) != 0 {
+		*text = append(*text, "", indent+"Background:")
+		appendGherkinSteps(steps, indent+"  ", text)
+	}
+}
+
+// appendGherkinMethods adds methods of the suite as scenarios, the empty suite is of test funcs.
+func appendGherkinMethods(data *TestData, suite string, indent string, text *[]string) {
+	for _, method := range data.methods {
+		if method.suite == suite {
+			appendGherkinScenario(method, nil, data.toc[method.name].gitLink, indent, text)
+		}
+	}
+}
+
+// appendGherkinScenario adds the method as a scenario and its subtests as following scenarios
+// with tags of the parent. Cases of a table-driven test are examples of a scenario outline.
+func appendGherkinScenario(method TestMethod, parentTags []string, gitLink string, indent string, text *[]string) {
+	tags := append(append([]string(nil), parentTags...), method.tags...)
+	*text = append(*text, "")
+	if gitLink != "" {
+		*text = append(*text, indent+"# Source: "+gitLink)
+	}
+	if len(tags) != 0 {
+		*text = append(*text, indent+getGherkinTags(tags))
+	}
+
+	keyword := "Scenario: "
+	if len(method.cases) != 0 {
+		keyword = "Scenario Outline: "
+	}
+	name := method.scenario
+	if name == "" {
+		name = method.name
+	}
+	*text = append(*text, indent+keyword+name)
+
+	for _, line := range method.description {
+		*text = append(*text, strings.TrimRight(indent+"  "+line, " "))
+	}
+	appendGherkinSteps(method.steps, indent+"  ", text)
+	appendGherkinExamples(method.cases, indent+"  ", text)
+
+	for _, subtest := range method.subtests {
+		appendGherkinScenario(subtest, tags, "", indent, text)
+	}
+}
+
+// appendGherkinSteps adds 'GWT' steps with their keywords ("*" if there is no keyword), indented steps
+// after a step are its doc string and indented steps before the first one are the description.
+func appendGherkinSteps(steps []TestStep, indent string, text *[]string) {
+	var docString []string
+	closeDocString := func() {
+		if docString != nil {
+			*text = append(*text, indent+`  """`)
+			*text = append(*text, docString...)
+			*text = append(*text, indent+`  """`)
+			docString = nil
+		}
+	}
+
+	hasStep := false
+	for _, step := range steps {
+		if step.kind.IsGWT() {
+			closeDocString()
+			*text = append(*text, indent+getGherkinStep(step))
+			hasStep = true
+			continue
+		}
+		line := strings.Repeat("  ", int(step.kind)-1) + "- " + step.comment
+		if !hasStep {
+			*text = append(*text, indent+line)
+			continue
+		}
+		docString = append(docString, indent+"  "+line)
+	}
+	closeDocString()
+}
+
+// appendGherkinExamples adds cases as the examples table with the name and all fields as columns.
+func appendGherkinExamples(cases []TestCase, indent string, text *[]string) {
+	if len(cases) == 0 {
+		return
+	}
+	columns := []string{"name"}
+	for _, testCase := range cases {
+		for _, field := range testCase.fields {
+			if !containsString(columns, field.name) {
+				columns = append(columns, field.name)
+			}
+		}
+	}
+
+	*text = append(*text, "", indent+"Examples:", indent+"  "+getGherkinRow(columns))
+	for _, testCase := range cases {
+		row := []string{testCase.name}
+		for _, column := range columns[1:] {
+			var value string
+			for _, field := range testCase.fields {
+				if field.name == column {
+					value = field.value
+				}
+			}
+			row = append(row, value)
+		}
+		*text = append(*text, indent+"  "+getGherkinRow(row))
+	}
+}
+
+// getGherkinStep returns the step with the Gherkin keyword of its kind, "*" if it has no keyword.
+func getGherkinStep(step TestStep) string {
+	if keyword, ok := gherkinKeywords[step.kind]; ok {
+		return strings.TrimSpace(keyword + " " + step.comment)
+	}
+	return "* " + step.comment
+}
+
+// getGherkinTags returns tags as "@tag" words, spaces of a tag are replaced with '_'.
+func getGherkinTags(tags []string) string {
+	var words []string
+	for _, tag := range tags {
+		words = append(words, "@"+strings.Join(strings.FieldsFunc(tag, unicode.IsSpace), "_"))
+	}
+	return strings.Join(words, " ")
+}
+
+func getGherkinRow(cells []string) string {
+	var row []string
+	for _, cell := range cells {
+		row = append(row, strings.ReplaceAll(strings.ReplaceAll(cell, `\`, `\\`), "|", `\|`))
+	}
+	return "| " + strings.Join(row, " | ") + " |"
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,195 @@
+package tc2mdc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGherkinMethod(t *testing.T) {
+	// > Write to Gherkin
+	// # Gherkin writer returns a feature with a scenario per method and Given/When/Then steps
+	// ## GIVEN - testData: "packageName" = 'pkg' with 1 method 'TestA':
+	var testData = &TestData{packageName: "pkg"}
+	testData.methods = []TestMethod{{
+		name: "TestA",
+		// - "tags" = 'T1', 'Complex tag', "scenario" = 'Returns nil'
+		tags:     []string{"T1", "Complex tag"},
+		scenario: "Returns nil",
+		// - "steps": GIVEN 'input', indented 'Step1', 'Step2', WHEN 'act', THEN 'check', 'GWT' 'log'
+		steps: []TestStep{{Given, "input"}, {Common, "Step1"}, {Indented, "Step2"},
+			{When, "act"}, {Then, "check"}, {GWT, "log"}},
+		// - 1 subtest 'TestA/child' with scenario 'Child'
+		subtests: []TestMethod{{name: "TestA/child", scenario: "Child"}},
+	}}
+	writer, err := GetWriter("gherkin")
+	require.Nil(t, err, "must be no error")
+
+	// ## WHEN Write()
+	text := writer.Write(testData)
+
+	// ## THEN - text is:
+	require.Equal(t, []string{
+		"Feature: pkg",
+		"",
+		"  @T1 @Complex_tag",
+		"  Scenario: Returns nil",
+		"    Given input",
+		`      """`,
+		"      - Step1",
+		"        - Step2",
+		`      """`,
+		"    When act",
+		"    Then check",
+		"    * log",
+		"",
+		"  @T1 @Complex_tag",
+		"  Scenario: Child",
+	}, text)
+	// - extension is '.feature', there is no index
+	require.Equal(t, ".feature", writer.Extension())
+	require.Nil(t, writer.WriteIndex([]*TestData{testData}, []string{"pkg.feature"}))
+}
+
+func TestGherkinOutline(t *testing.T) {
+	// > Write to Gherkin
+	// # Cases of a table-driven test are examples of a scenario outline
+	// ## GIVEN - testData: "title" = 'Title' with 1 method 'TestA' without scenario:
+	var testData = &TestData{title: "Title", packageName: "pkg"}
+	testData.methods = []TestMethod{{
+		name: "TestA",
+		// - "cases": 'c1' with field {'in', '1'}, 'c|2' with fields {'in', '2'}, {'out', 'a|b'}
+		cases: []TestCase{
+			{name: "c1", fields: []TestField{{"in", "1"}}},
+			{name: "c|2", fields: []TestField{{"in", "2"}, {"out", "a|b"}}},
+		},
+	}}
+
+	// ## WHEN Write() in Gherkin
+	text := writers["gherkin"].Write(testData)
+
+	// ## THEN - text is a feature named by the title, a scenario outline named by the method
+	// -- all fields are columns of examples, '|' is escaped
+	require.Equal(t, []string{
+		"Feature: Title",
+		"",
+		"  Scenario Outline: TestA",
+		"",
+		"    Examples:",
+		"      | name | in | out |",
+		"      | c1 | 1 |  |",
+		`      | c\|2 | 2 | a\|b |`,
+	}, text)
+}
+
+func TestGherkinAll(t *testing.T) {
+	// > Write to Gherkin
+	// # Several packages are rules of one feature
+	// ## GIVEN - packages 'p1' with method 'TestA' and 'p2' with title 'Second' and method 'TestB'
+	var p1 = &TestData{packageName: "p1", methods: []TestMethod{{name: "TestA"}}}
+	var p2 = &TestData{title: "Second", packageName: "p2", methods: []TestMethod{{name: "TestB"}}}
+
+	// ## WHEN WriteAll() in Gherkin
+	text := writers["gherkin"].WriteAll([]*TestData{p1, nil, p2})
+
+	// ## THEN - text is a feature named by the first title with a rule per package
+	require.Equal(t, []string{
+		"Feature: Second",
+		"",
+		"  Rule: p1",
+		"",
+		"    Scenario: TestA",
+		"",
+		"  Rule: Second",
+		"",
+		"    Scenario: TestB",
+	}, text)
+	// - no text without test data
+	require.Nil(t, writers["gherkin"].WriteAll(nil))
+}
+
+func TestGherkinBackground(t *testing.T) {
+	// > Write to Gherkin, Background
+	// # Gherkin writer returns the background of the package before scenarios
+	// ## GIVEN - testData: "packageName" = 'pkg' with a background step and 1 method 'TestA'
+	var testData = &TestData{packageName: "pkg", background: []TestStep{{Given, "started server"}},
+		methods: []TestMethod{{name: "TestA", steps: []TestStep{{When, "act"}}}}}
+	writer, err := GetWriter("gherkin")
+	require.Nil(t, err, "must be no error")
+
+	// ## WHEN Write()
+	text := writer.Write(testData)
+
+	// ## THEN - text is:
+	require.Equal(t, []string{
+		"Feature: pkg",
+		"",
+		"  Background:",
+		"    Given started server",
+		"",
+		"  Scenario: TestA",
+		"    When act",
+	}, text)
+}
+
+func TestGherkinSuite(t *testing.T) {
+	// > Write to Gherkin, Suites
+	// # Gherkin writer returns a suite as a rule with the background of the suite after scenarios of test funcs
+	// ## GIVEN - testData: "packageName" = 'pkg' with a background step, a test func 'TestA'
+	// and a suite 'DBSuite' with a background step and a method 'DBSuite.TestInsert'
+	var testData = &TestData{packageName: "pkg", background: []TestStep{{Given, "started server"}},
+		suites: []TestSuite{{name: "DBSuite", background: []TestStep{{Given, "connected db"}}}},
+		methods: []TestMethod{
+			{name: "DBSuite.TestInsert", suite: "DBSuite", steps: []TestStep{{When, "insert"}}},
+			{name: "TestA", steps: []TestStep{{When, "act"}}},
+		}}
+
+	// ## WHEN Write() in Gherkin
+	text := writers["gherkin"].Write(testData)
+
+	// ## THEN - text is the feature with the rule of the suite
+	require.Equal(t, []string{
+		"Feature: pkg",
+		"",
+		"  Background:",
+		"    Given started server",
+		"",
+		"  Scenario: TestA",
+		"    When act",
+		"",
+		"  Rule: DBSuite",
+		"",
+		"    Background:",
+		"      Given connected db",
+		"",
+		"    Scenario: DBSuite.TestInsert",
+		"      When insert",
+	}, text)
+
+	// ## WHEN WriteAll() of the package and another one
+	text = writers["gherkin"].WriteAll([]*TestData{testData, {packageName: "other"}})
+
+	// ## THEN - the suite is a rule after the rule of the package with both backgrounds
+	require.Equal(t, []string{
+		"Feature: Test scenarios",
+		"",
+		"  Rule: pkg",
+		"",
+		"    Background:",
+		"      Given started server",
+		"",
+		"    Scenario: TestA",
+		"      When act",
+		"",
+		"  Rule: pkg/DBSuite",
+		"",
+		"    Background:",
+		"      Given started server",
+		"      Given connected db",
+		"",
+		"    Scenario: DBSuite.TestInsert",
+		"      When insert",
+		"",
+		"  Rule: other",
+	}, text)
+}
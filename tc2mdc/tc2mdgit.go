@@ -0,0 +1,254 @@
+package tc2mdc
+
+import (
+	"bufio"
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// GitRepo is a remote repository to link test sources to.
+type GitRepo struct {
+	root    string // local directory of the repository, source paths are relative to it
+	baseURL string // web URL of the repository, e.g. "https://github.com/owner/repo"
+	ref     string // branch, tag or commit
+}
+
+var (
+	reCommit    = regexp.MustCompile(`^[0-9a-f]{40}$`)
+	reSCPRemote = regexp.MustCompile(`^(?:[\w.-]+@)?(?P<host>[\w.-]+):(?P<path>[^/].*)$`) // git@host:owner/repo.git
+)
+
+// NewGitRepo returns a repository with the given web URL and ref checked out in the "root" directory.
+func NewGitRepo(root string, baseURL string, ref string) *GitRepo {
+	return &GitRepo{root: root, baseURL: strings.TrimSuffix(baseURL, "/"), ref: ref}
+}
+
+// DetectGitRepo finds the ".git" directory upward from the path and returns the repository
+// with URL of the "origin" (or first) remote and the commit of HEAD.
+func DetectGitRepo(path string) (*GitRepo, error) {
+	root, gitDir, err := findGitDir(path)
+	if err != nil {
+		return nil, err
+	}
+	commonDir, err := getCommonDir(gitDir)
+	if err != nil {
+		return nil, err
+	}
+	remote, err := readRemoteURL(filepath.Join(commonDir, "config"))
+	if err != nil {
+		return nil, err
+	}
+	ref, err := readHead(gitDir, commonDir)
+	if err != nil {
+		return nil, err
+	}
+	return NewGitRepo(root, getWebURL(remote), ref), nil
+}
+
+// Root returns the local directory of the repository.
+func (repo *GitRepo) Root() string {
+	return repo.root
+}
+
+// BaseURL returns the web URL of the repository.
+func (repo *GitRepo) BaseURL() string {
+	return repo.baseURL
+}
+
+// Ref returns the branch, tag or commit links refer to.
+func (repo *GitRepo) Ref() string {
+	return repo.ref
+}
+
+// GetLink returns the web link to the line of the source file,
+// the URL layout is chosen by the host: GitLab, Gitea (Codeberg, Forgejo) or GitHub by default.
+func (repo *GitRepo) GetLink(file string, line int) string {
+	path := file
+	if absFile, err := filepath.Abs(file); err == nil {
+		if relFile, err := filepath.Rel(repo.root, absFile); err == nil {
+			path = relFile
+		}
+	}
+	path = filepath.ToSlash(path)
+
+	var link string
+	switch host := strings.ToLower(repo.baseURL); {
+	case strings.Contains(host, "gitlab"):
+		{
+			link = repo.baseURL + "/-/blob/" + repo.ref + "/" + path
+		}
+	case strings.Contains(host, "gitea"), strings.Contains(host, "codeberg"), strings.Contains(host, "forgejo"):
+		{
+			kind := "branch"
+			if reCommit.MatchString(repo.ref) {
+				kind = "commit"
+			}
+			link = repo.baseURL + "/src/" + kind + "/" + repo.ref + "/" + path
+		}
+	default:
+		{
+			link = repo.baseURL + "/blob/" + repo.ref + "/" + path
+		}
+	}
+	return link + "#L" + strconv.Itoa(line)
+}
+
+// SetGitLinks sets the link to the source line of each test method to its TOC line.
+func SetGitLinks(data *TestData, repo *GitRepo) {
+	if data == nil || repo == nil {
+		return
+	}
+	for _, method := range data.methods {
+		line, ok := data.toc[method.name]
+		if !ok || method.file == "" || method.line == 0 {
+			continue
+		}
+		line.gitLink = repo.GetLink(method.file, method.line)
+		data.toc[method.name] = line
+	}
+}
+
+// findGitDir returns the repository root and its git directory, a ".git" file of a worktree is followed
+// to the git directory of the worktree, see getCommonDir().
+func findGitDir(path string) (string, string, error) {
+	dir, err := filepath.Abs(path)
+	if err != nil {
+		return "", "", err
+	}
+	for {
+		gitPath := filepath.Join(dir, ".git")
+		if info, err := os.Stat(gitPath); err == nil {
+			if info.IsDir() {
+				return dir, gitPath, nil
+			}
+			content, err := os.ReadFile(gitPath)
+			if err != nil {
+				return "", "", err
+			}
+			gitDir := strings.TrimSpace(strings.TrimPrefix(string(content), "gitdir:"))
+			if !filepath.IsAbs(gitDir) {
+				gitDir = filepath.Join(dir, gitDir)
+			}
+			return dir, gitDir, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", "", errors.New("no git repository found for " + path)
+		}
+		dir = parent
+	}
+}
+
+// getCommonDir returns the directory of the config and refs shared by worktrees: the one of "commondir"
+// in the git directory of a worktree, the git directory itself otherwise.
+func getCommonDir(gitDir string) (string, error) {
+	content, err := os.ReadFile(filepath.Join(gitDir, "commondir"))
+	if errors.Is(err, os.ErrNotExist) {
+		return gitDir, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	commonDir := strings.TrimSpace(string(content))
+	if !filepath.IsAbs(commonDir) {
+		commonDir = filepath.Join(gitDir, commonDir)
+	}
+	return commonDir, nil
+}
+
+// readRemoteURL returns "url" of the "origin" remote, or of the first one if there is no "origin".
+func readRemoteURL(configPath string) (string, error) {
+	file, err := os.Open(configPath)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	var remote, firstURL string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case strings.HasPrefix(line, "["):
+			{
+				remote = ""
+				if strings.HasPrefix(line, "[remote ") {
+					remote = strings.Trim(line[len("[remote "):], `"]`)
+				}
+			}
+		case remote != "" && strings.HasPrefix(line, "url"):
+			{
+				key, value, found := strings.Cut(line, "=")
+				if !found || strings.TrimSpace(key) != "url" {
+					continue
+				}
+				value = strings.TrimSpace(value)
+				if remote == "origin" {
+					return value, nil
+				}
+				if firstURL == "" {
+					firstURL = value
+				}
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	if firstURL == "" {
+		return "", errors.New("no remote found in " + configPath)
+	}
+	return firstURL, nil
+}
+
+// readHead returns the commit of HEAD of the git directory, or the branch name if its ref can't be resolved
+// in the common directory.
+func readHead(gitDir string, commonDir string) (string, error) {
+	content, err := os.ReadFile(filepath.Join(gitDir, "HEAD"))
+	if err != nil {
+		return "", err
+	}
+	head := strings.TrimSpace(string(content))
+	ref, found := strings.CutPrefix(head, "ref: ")
+	if !found {
+		return head, nil // detached HEAD
+	}
+	if commit, err := os.ReadFile(filepath.Join(commonDir, filepath.FromSlash(ref))); err == nil {
+		return strings.TrimSpace(string(commit)), nil
+	}
+	if packed, err := os.ReadFile(filepath.Join(commonDir, "packed-refs")); err == nil {
+		for _, line := range strings.Split(string(packed), "\n") {
+			commit, name, found := strings.Cut(strings.TrimSpace(line), " ")
+			if found && name == ref {
+				return commit, nil
+			}
+		}
+	}
+	return strings.TrimPrefix(ref, "refs/heads/"), nil
+}
+
+// getWebURL converts a remote URL (https, ssh or scp-like) to the web URL of the repository.
+func getWebURL(remote string) string {
+	webURL := strings.TrimSuffix(strings.TrimSuffix(remote, "/"), ".git")
+	if scheme, rest, found := strings.Cut(webURL, "://"); found {
+		if at := strings.Index(rest, "@"); at >= 0 && at < strings.Index(rest+"/", "/") {
+			rest = rest[at+1:] // drop user info
+		}
+		if scheme != "http" && scheme != "https" {
+			scheme = "https"
+			host, path, _ := strings.Cut(rest, "/")
+			host, _, _ = strings.Cut(host, ":") // drop ssh port
+			rest = host + "/" + path
+		}
+		return scheme + "://" + rest
+	}
+	result := getMatchesMap(reSCPRemote, webURL)
+	if result == nil {
+		return webURL
+	}
+	return "https://" + result["host"] + "/" + result["path"]
+}
@@ -0,0 +1,133 @@
+package tc2mdc
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitLinkHosts(t *testing.T) {
+	// > Git
+	// # GetLink() returns a link to the source line in the layout of the repository host
+	// ## GIVEN repositories in '/repo' with ref 'main' hosted on:
+	var repos = []*GitRepo{
+		// - GitHub
+		NewGitRepo("/repo", "https://github.com/owner/repo/", "main"),
+		// - GitLab
+		NewGitRepo("/repo", "https://gitlab.com/group/repo", "main"),
+		// - Gitea
+		NewGitRepo("/repo", "https://gitea.example.com/owner/repo", "main"),
+	}
+
+	// ## WHEN GetLink('/repo/pkg/a_test.go', 12)
+	var links []string
+	for _, repo := range repos {
+		links = append(links, repo.GetLink("/repo/pkg/a_test.go", 12))
+	}
+
+	// ## THEN links are:
+	require.Equal(t, []string{
+		// - 'https://github.com/owner/repo/blob/main/pkg/a_test.go#L12'
+		"https://github.com/owner/repo/blob/main/pkg/a_test.go#L12",
+		// - 'https://gitlab.com/group/repo/-/blob/main/pkg/a_test.go#L12'
+		"https://gitlab.com/group/repo/-/blob/main/pkg/a_test.go#L12",
+		// - 'https://gitea.example.com/owner/repo/src/branch/main/pkg/a_test.go#L12'
+		"https://gitea.example.com/owner/repo/src/branch/main/pkg/a_test.go#L12",
+	}, links)
+}
+
+func TestGitWebURL(t *testing.T) {
+	// > Git
+	// # getWebURL() converts remote URLs to web URLs of the repository
+	// ## GIVEN remote URLs and expected web URLs:
+	var remotes = map[string]string{
+		// - 'git@github.com:owner/repo.git' -> 'https://github.com/owner/repo'
+		"git@github.com:owner/repo.git": "https://github.com/owner/repo",
+		// - 'ssh://git@host:2222/owner/repo.git' -> 'https://host/owner/repo'
+		"ssh://git@host:2222/owner/repo.git": "https://host/owner/repo",
+		// - 'https://user@host/owner/repo.git' -> 'https://host/owner/repo'
+		"https://user@host/owner/repo.git": "https://host/owner/repo",
+		// - 'https://host:8443/owner/repo' -> unchanged
+		"https://host:8443/owner/repo": "https://host:8443/owner/repo",
+	}
+
+	for remote, expected := range remotes {
+		// ## WHEN getWebURL()
+		webURL := getWebURL(remote)
+		// ## THEN web URL is as expected
+		require.Equal(t, expected, webURL, remote)
+	}
+}
+
+func TestGitDetectRepo(t *testing.T) {
+	// > Git
+	// # DetectGitRepo() reads the "origin" remote and HEAD commit from the ".git" directory upward
+	// ## GIVEN a repository directory with:
+	root := t.TempDir()
+	gitDir := filepath.Join(root, ".git")
+	// - "config" with 'upstream' and 'origin' remotes
+	writeTestFile(t, filepath.Join(gitDir, "config"), "[core]\n\tbare = false\n"+
+		"[remote \"upstream\"]\n\turl = https://github.com/other/repo.git\n"+
+		"[remote \"origin\"]\n\turl = git@github.com:owner/repo.git\n")
+	// - "HEAD" referring 'refs/heads/main' which is in "packed-refs" only
+	writeTestFile(t, filepath.Join(gitDir, "HEAD"), "ref: refs/heads/main\n")
+	writeTestFile(t, filepath.Join(gitDir, "packed-refs"), "# pack-refs\n0123456789abcdef0123456789abcdef01234567 refs/heads/main\n")
+	// - a package directory 'pkg'
+	require.Nil(t, os.MkdirAll(filepath.Join(root, "pkg"), 0o755))
+
+	// ## WHEN DetectGitRepo('pkg')
+	repo, err := DetectGitRepo(filepath.Join(root, "pkg"))
+
+	// ## THEN no error, the repository has:
+	require.Nil(t, err, "must be no error")
+	// - "root" = the repository directory, "baseURL" = 'https://github.com/owner/repo', "ref" = the commit
+	require.Equal(t, NewGitRepo(root, "https://github.com/owner/repo", "0123456789abcdef0123456789abcdef01234567"), repo)
+
+	// ## WHEN DetectGitRepo() of a worktree 'wt' with a ".git" file
+	worktree := filepath.Join(root, "wt")
+	worktreeGitDir := filepath.Join(gitDir, "worktrees", "wt")
+	writeTestFile(t, filepath.Join(worktree, ".git"), "gitdir: "+worktreeGitDir+"\n")
+	// - the git directory of the worktree has "HEAD" referring 'refs/heads/feature' and "commondir"
+	writeTestFile(t, filepath.Join(worktreeGitDir, "HEAD"), "ref: refs/heads/feature\n")
+	writeTestFile(t, filepath.Join(worktreeGitDir, "commondir"), "../..\n")
+	// - 'refs/heads/feature' is in the common git directory
+	writeTestFile(t, filepath.Join(gitDir, "refs", "heads", "feature"), "89abcdef0123456789abcdef0123456789abcdef\n")
+	repo, err = DetectGitRepo(worktree)
+
+	// ## THEN the remote and the ref are read from the common directory with HEAD of the worktree
+	require.Nil(t, err, "must be no error")
+	require.Equal(t, NewGitRepo(worktree, "https://github.com/owner/repo", "89abcdef0123456789abcdef0123456789abcdef"), repo)
+}
+
+func TestGitSetLinks(t *testing.T) {
+	// > Git, TOC
+	// # SetGitLinks() sets "gitLink" of TOC lines of methods with a known source position
+	// ## GIVEN - testData with 2 methods and TOC lines:
+	var testData = &TestData{
+		// - 'TestA' in '/repo/a_test.go' at line 5
+		// - 'TestB' without a source position
+		methods: []TestMethod{{name: "TestA", file: "/repo/a_test.go", line: 5}, {name: "TestB"}},
+		toc: map[string]TOCLine{
+			"TestA": {index: 0, caption: "A", link: "#testa"},
+			"TestB": {index: 1, caption: "B", link: "#testb"},
+		},
+	}
+
+	// ## WHEN SetGitLinks() of GitHub repository in '/repo' with ref 'v1'
+	SetGitLinks(testData, NewGitRepo("/repo", "https://github.com/owner/repo", "v1"))
+
+	// ## THEN TOC lines are:
+	require.Equal(t, map[string]TOCLine{
+		// - 'TestA' with "gitLink" = 'https://github.com/owner/repo/blob/v1/a_test.go#L5'
+		"TestA": {index: 0, caption: "A", link: "#testa", gitLink: "https://github.com/owner/repo/blob/v1/a_test.go#L5"},
+		// - 'TestB' without "gitLink"
+		"TestB": {index: 1, caption: "B", link: "#testb"},
+	}, testData.toc)
+}
+
+func writeTestFile(t *testing.T, path string, content string) {
+	require.Nil(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.Nil(t, os.WriteFile(path, []byte(content), 0o644))
+}
@@ -0,0 +1,197 @@
+package tc2mdc
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Names of the config file of a grammar in the order of the search in a directory
+var grammarFiles = []string{".tc2md.yaml", ".tc2md.yml", ".tc2md.toml"}
+
+// Grammar is the syntax of markers: the prefix of one line comments with markers, tokens of markers
+// and the nesting depth of bullet steps. Bullet tokens of deeper levels repeat the bullet one, e.g. "--".
+type Grammar struct {
+	Prefix   string `yaml:"prefix" toml:"prefix"`     // starts with "//", e.g. "//:"; "/*" + the rest starts block comments
+	Title    string `yaml:"title" toml:"title"`       // file-level title marker
+	Scenario string `yaml:"scenario" toml:"scenario"` // scenario marker
+	Tags     string `yaml:"tags" toml:"tags"`         // comma-separated tags marker
+	Step     string `yaml:"step" toml:"step"`         // 'GWT' step marker
+	Bullet   string `yaml:"bullet" toml:"bullet"`     // bullet step marker of the first level
+	Depth    int    `yaml:"depth" toml:"depth"`       // levels of bullet steps, 1 to 3
+}
+
+// Roles of marker tokens
+const (
+	roleScenario = iota
+	roleTags
+	roleStep
+	roleBullet
+)
+
+// markerGrammar is the compiled Grammar.
+type markerGrammar struct {
+	prefix     string              // prefix of one line comments
+	blockStart string              // start of block comments
+	title      *regexp.Regexp      // title of a comment text after the prefix
+	marker     *regexp.Regexp      // marker token of a comment text after the prefix
+	tokens     []string            // all marker tokens
+	roles      map[string]int      // roles by token
+	bullets    map[string]StepKind // kinds of bullet tokens
+	chars      string              // characters of tokens
+}
+
+// Grammar of the "// #", "// ##", "// >" and "// -" markers
+var defaultGrammar = mustCompile(DefaultGrammar())
+
+// DefaultGrammar returns the grammar of "// #! Title", "// # Scenario", "// > Tags", "// ## Step"
+// and "// -", "// --", "// ---" bullet steps.
+func DefaultGrammar() Grammar {
+	return Grammar{Prefix: OLC, Title: TitleMarker, Scenario: "#", Tags: ">", Step: "##", Bullet: "-", Depth: 3}
+}
+
+// FindGrammar returns the grammar of the config file found in the directory of the path or in its parents
+// and the path of the file. Markers which are not set in the file are default ones, the default grammar
+// is returned with the empty path if there is no config file.
+func FindGrammar(path string) (Grammar, string, error) {
+	dir, err := filepath.Abs(path)
+	if err != nil {
+		return Grammar{}, "", err
+	}
+	if info, err := os.Stat(dir); err == nil && !info.IsDir() {
+		dir = filepath.Dir(dir)
+	}
+	for {
+		for _, name := range grammarFiles {
+			configPath := filepath.Join(dir, name)
+			if _, err := os.Stat(configPath); err == nil {
+				grammar, err := ReadGrammar(configPath)
+				return grammar, configPath, err
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return DefaultGrammar(), "", nil
+		}
+		dir = parent
+	}
+}
+
+// ReadGrammar reads the grammar from a YAML or TOML (by ".toml" extension) config file
+// and checks it is valid, unknown keys are errors.
+func ReadGrammar(configPath string) (Grammar, error) {
+	content, err := os.ReadFile(configPath)
+	if err != nil {
+		return Grammar{}, err
+	}
+	grammar := DefaultGrammar()
+	if filepath.Ext(configPath) == ".toml" {
+		var meta toml.MetaData
+		if meta, err = toml.NewDecoder(bytes.NewReader(content)).Decode(&grammar); err == nil && len(meta.Undecoded()) > 0 {
+			err = errors.New("field " + meta.Undecoded()[0].String() + " not found") // as unknown fields of YAML
+		}
+	} else {
+		decoder := yaml.NewDecoder(bytes.NewReader(content))
+		decoder.KnownFields(true)
+		if err = decoder.Decode(&grammar); errors.Is(err, io.EOF) {
+			err = nil // empty file
+		}
+	}
+	if err != nil {
+		return Grammar{}, errors.New(configPath + ": " + err.Error())
+	}
+	if _, err := compileGrammar(grammar); err != nil {
+		return Grammar{}, errors.New(configPath + ": " + err.Error())
+	}
+	return grammar, nil
+}
+
+// ParseFile parses the source of a Go test file with the markers of the grammar, see ParseFile().
+func (grammar Grammar) ParseFile(filename string, src []byte) (*TestData, error) {
+	compiled, err := compileGrammar(grammar)
+	if err != nil {
+		return nil, err
+	}
+	return parseFile(filename, src, compiled)
+}
+
+// Lint returns issues of a Go test file with the markers of the grammar, see Lint().
+func (grammar Grammar) Lint(filename string, src []byte) ([]LintIssue, error) {
+	compiled, err := compileGrammar(grammar)
+	if err != nil {
+		return nil, err
+	}
+	return lint(filename, src, compiled)
+}
+
+// compileGrammar checks the grammar: the prefix is a one line comment, tokens are not empty, have no spaces
+// and differ from each other, the depth is 1 to 3.
+func compileGrammar(grammar Grammar) (*markerGrammar, error) {
+	if !strings.HasPrefix(grammar.Prefix, OLC) || strings.ContainsAny(grammar.Prefix, " \t") {
+		return nil, errors.New("prefix must start with \"" + OLC + "\" and have no spaces: \"" + grammar.Prefix + "\"")
+	}
+	if grammar.Depth < 1 || grammar.Depth > int(Indented2) {
+		return nil, errors.New("depth must be 1 to " + strconv.Itoa(int(Indented2)) + ": " + strconv.Itoa(grammar.Depth))
+	}
+
+	compiled := &markerGrammar{prefix: grammar.Prefix, blockStart: BCStart + grammar.Prefix[len(OLC):],
+		roles:   map[string]int{grammar.Scenario: roleScenario, grammar.Tags: roleTags, grammar.Step: roleStep},
+		bullets: make(map[string]StepKind)}
+	var bullets []string
+	for level := 1; level <= grammar.Depth; level++ {
+		bullet := strings.Repeat(grammar.Bullet, level)
+		bullets = append(bullets, bullet)
+		compiled.bullets[bullet] = StepKind(level)
+	}
+	for _, token := range append([]string{grammar.Title, grammar.Scenario, grammar.Tags, grammar.Step}, bullets...) {
+		if token == "" || strings.ContainsAny(token, " \t") {
+			return nil, errors.New("marker must not be empty or have spaces: \"" + token + "\"")
+		}
+		if strings.HasPrefix(token, "*") {
+			// a leading '*' of lines of block comments is skipped, see getBlockLines()
+			return nil, errors.New("marker must not start with \"*\": \"" + token + "\"")
+		}
+		if containsString(compiled.tokens, token) {
+			return nil, errors.New("marker is used twice: \"" + token + "\"")
+		}
+		compiled.tokens = append(compiled.tokens, token)
+		for _, r := range token {
+			if !strings.ContainsRune(compiled.chars, r) {
+				compiled.chars += string(r)
+			}
+		}
+	}
+	compiled.tokens = compiled.tokens[1:] // the title is a file-level marker
+	for _, bullet := range bullets {
+		compiled.roles[bullet] = roleBullet
+	}
+
+	var quoted []string
+	for _, token := range compiled.tokens {
+		quoted = append(quoted, regexp.QuoteMeta(token))
+	}
+	var err error
+	if compiled.marker, err = regexp.Compile(`^\s(` + strings.Join(quoted, "|") + `)\s[^\s]`); err != nil {
+		return nil, err
+	}
+	if compiled.title, err = regexp.Compile(`^\s` + regexp.QuoteMeta(grammar.Title) + `\s+(?P<title>\S.*)`); err != nil {
+		return nil, err
+	}
+	return compiled, nil
+}
+
+func mustCompile(grammar Grammar) *markerGrammar {
+	compiled, err := compileGrammar(grammar)
+	if err != nil {
+		panic(err)
+	}
+	return compiled
+}
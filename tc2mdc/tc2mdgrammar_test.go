@@ -0,0 +1,180 @@
+package tc2mdc
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGrammarCustomMarkers(t *testing.T) {
+	// > Grammar
+	// # Grammar.ParseFile() parses markers of a custom grammar and skips default ones
+	// ## GIVEN Grammar with "//:" prefix, "@" tags, "=" steps, "+" bullets of depth 2
+	grammar := Grammar{Prefix: "//:", Title: "title", Scenario: "#", Tags: "@", Step: "=", Bullet: "+", Depth: 2}
+	// ## AND Input is
+	var input = strings.Join([]string{
+		"//: title Custom markers",
+		"package somePackage",
+		"",
+		"import \"testing\"",
+		"",
+		"//: # Scenario",
+		"// Description",
+		"func TestCustom(t *testing.T) {",
+		"	//: @ tag",
+		"	//: = GIVEN set",
+		"	//: + first",
+		"	//: ++ second",
+		"	//: +++ third",
+		"	/*: = WHEN act */",
+		"	// ## THEN default marker",
+		"	//:= THEN check",
+		"}",
+	}, "\n")
+
+	// ## WHEN Grammar.ParseFile()
+	data, err := grammar.ParseFile("some_test.go", []byte(input))
+	// ## THEN the title, scenario, tags and steps are parsed with the custom markers
+	require.NotNil(t, data, "must be parsed")
+	require.Equal(t, "Custom markers", data.Title())
+	method := data.Methods()[0]
+	require.Equal(t, "Scenario", method.Scenario())
+	require.Equal(t, []string{"Description"}, method.Description())
+	require.Equal(t, []string{"tag"}, method.Tags())
+	require.Equal(t, []TestStep{
+		{Given, "set"}, {Common, "first"}, {Indented, "second"}, {When, "act"},
+	}, method.Steps())
+	// ## AND malformed markers of the grammar are errors, "+++" is unknown as the depth is 2
+	var lines []string
+	for _, parseError := range err.(ParseErrors) {
+		lines = append(lines, parseError.Error())
+	}
+	require.Equal(t, []string{
+		`some_test.go:13:6: unknown marker "+++"`,
+		`some_test.go:16:5: no space between "//:" and marker "="`,
+	}, lines)
+}
+
+func TestGrammarBlockDocComment(t *testing.T) {
+	// > Grammar
+	// # Grammar.ParseFile() parses markers of a block doc comment with the prefix of the grammar
+	// ## GIVEN Grammar with "//spec" prefix
+	grammar := DefaultGrammar()
+	grammar.Prefix = "//spec"
+	// ## AND Input is a test with a "/*spec" doc comment with a scenario and a bullet
+	var input = strings.Join([]string{
+		"package somePackage",
+		"",
+		"/*spec",
+		"# Scenario",
+		"- item",
+		"*/",
+		"func TestBlock(t *testing.T) {",
+		"}",
+	}, "\n")
+
+	// ## WHEN Grammar.ParseFile()
+	data, err := grammar.ParseFile("some_test.go", []byte(input))
+	// ## THEN the scenario and the step are parsed without the description
+	require.Nil(t, err, "must be no error")
+	method := data.Methods()[0]
+	require.Equal(t, "Scenario", method.Scenario())
+	require.Equal(t, []TestStep{{Common, "item"}}, method.Steps())
+	require.Nil(t, method.Description(), "description must be nil")
+}
+
+func TestGrammarDefault(t *testing.T) {
+	// > Grammar
+	// # ParseFile() of the default grammar is the same as ParseFile()
+	// ## GIVEN Input is a test with "// #" and "// ##" markers
+	var input = "package somePackage\n\nimport \"testing\"\n\n// # Scenario\nfunc TestA(t *testing.T) {\n\t// ## WHEN act\n}\n"
+	// ## WHEN DefaultGrammar().ParseFile() and ParseFile()
+	grammarData, grammarErr := DefaultGrammar().ParseFile("some_test.go", []byte(input))
+	data, err := ParseFile("some_test.go", []byte(input))
+	// ## THEN results are equal
+	require.Nil(t, grammarErr, "must be no error")
+	require.Nil(t, err, "must be no error")
+	require.Equal(t, data, grammarData)
+}
+
+func TestFindGrammar(t *testing.T) {
+	// > Grammar
+	// # FindGrammar() reads the config file of the directory or of the nearest parent
+	// ## GIVEN directories 'yaml/pkg', 'toml' and 'none'
+	root := t.TempDir()
+	for _, dir := range []string{"yaml/pkg", "toml", "none"} {
+		require.Nil(t, os.MkdirAll(filepath.Join(root, dir), 0o755))
+	}
+	// - 'yaml/.tc2md.yaml' sets the prefix and the step marker
+	yamlPath := filepath.Join(root, "yaml", ".tc2md.yaml")
+	require.Nil(t, os.WriteFile(yamlPath, []byte("prefix: \"//:\"\nstep: \"=\"\n"), 0o644))
+	// - 'toml/.tc2md.toml' sets the bullet marker and the depth
+	tomlPath := filepath.Join(root, "toml", ".tc2md.toml")
+	require.Nil(t, os.WriteFile(tomlPath, []byte("bullet = \"+\"\ndepth = 1\n"), 0o644))
+
+	// ## WHEN FindGrammar() of 'yaml/pkg/a_test.go'
+	grammar, path, err := FindGrammar(filepath.Join(root, "yaml", "pkg", "a_test.go"))
+	// ## THEN the grammar of the parent YAML file with other default markers is returned
+	require.Nil(t, err, "must be no error")
+	require.Equal(t, yamlPath, path)
+	expected := DefaultGrammar()
+	expected.Prefix, expected.Step = "//:", "="
+	require.Equal(t, expected, grammar)
+
+	// ## WHEN FindGrammar() of 'toml'
+	grammar, path, err = FindGrammar(filepath.Join(root, "toml"))
+	// ## THEN the grammar of the TOML file is returned
+	require.Nil(t, err, "must be no error")
+	require.Equal(t, tomlPath, path)
+	expected = DefaultGrammar()
+	expected.Bullet, expected.Depth = "+", 1
+	require.Equal(t, expected, grammar)
+
+	// ## WHEN FindGrammar() of 'none'
+	grammar, path, err = FindGrammar(filepath.Join(root, "none"))
+	// ## THEN the default grammar is returned without a path
+	require.Nil(t, err, "must be no error")
+	require.Equal(t, "", path)
+	require.Equal(t, DefaultGrammar(), grammar)
+}
+
+func TestReadGrammarErrors(t *testing.T) {
+	// > Grammar
+	// # ReadGrammar() returns an error of an invalid config
+	// ## GIVEN config files and their errors:
+	var configs = map[string]string{
+		// - the same token twice
+		"scenario: \"##\"\n": `marker is used twice: "##"`,
+		// - the depth out of 1 to 3
+		"depth: 4\n": "depth must be 1 to 3: 4",
+		// - the prefix is not a one line comment
+		"prefix: \"#\"\n": `prefix must start with "//" and have no spaces: "#"`,
+		// - a token with spaces
+		"tags: \"> >\"\n": `marker must not be empty or have spaces: "> >"`,
+		// - a token starting with '*' which is skipped in block comments
+		"bullet: \"*\"\n": `marker must not start with "*": "*"`,
+		// - an unknown key
+		"marker: \"#\"\n": "field marker not found",
+	}
+
+	configPath := filepath.Join(t.TempDir(), ".tc2md.yaml")
+	for config, expected := range configs {
+		require.Nil(t, os.WriteFile(configPath, []byte(config), 0o644))
+		// ## WHEN ReadGrammar()
+		_, err := ReadGrammar(configPath)
+		// ## THEN the error has the path of the file and the reason
+		require.NotNil(t, err, config)
+		require.True(t, strings.HasPrefix(err.Error(), configPath+": "), err.Error())
+		require.Contains(t, err.Error(), expected)
+	}
+
+	// ## WHEN ReadGrammar() of a TOML file with an unknown key
+	tomlPath := filepath.Join(t.TempDir(), ".tc2md.toml")
+	require.Nil(t, os.WriteFile(tomlPath, []byte("prefx = \"//:\"\n"), 0o644))
+	_, err := ReadGrammar(tomlPath)
+	// ## THEN the error has the path of the file and the key
+	require.EqualError(t, err, tomlPath+": field prefx not found")
+}
@@ -0,0 +1,205 @@
+package tc2mdc
+
+import (
+	"html"
+	"strconv"
+	"strings"
+)
+
+// Default title of HTML documents without a title
+const htmlTitle = "Test scenarios"
+
+// Style of HTML documents
+var htmlStyle = []string{
+	"body { font-family: -apple-system, 'Segoe UI', Helvetica, Arial, sans-serif; line-height: 1.5;",
+	"  max-width: 960px; margin: 0 auto; padding: 2em; color: #1f2328; }",
+	"code, pre { font-family: ui-monospace, Menlo, Consolas, monospace; background: #f6f8fa; border-radius: 4px; }",
+	"code { padding: 0.1em 0.3em; }",
+	"pre { padding: 1em; overflow: auto; }",
+	"hr { border: 0; border-top: 1px solid #d1d9e0; margin: 2em 0 1em; }",
+	"blockquote.tags { margin: 0; padding: 0 1em; color: #59636e; border-left: 0.25em solid #d1d9e0; }",
+	"ul.steps { list-style: disc; }",
+	"ul.steps li.level-2 { margin-left: 1.5em; }",
+	"ul.steps li.level-3 { margin-left: 3em; }",
+	"table { border-collapse: collapse; }",
+	"th, td { border: 1px solid #d1d9e0; padding: 0.3em 0.8em; text-align: left; }",
+	".pass { color: #1a7f37; } .fail { color: #d1242f; } .skip { color: #9a6700; }",
+	"details { margin: 0.5em 0; }",
+}
+
+// htmlMarkup is the format of a standalone HTML document with CSS.
+type htmlMarkup struct{}
+
+func (htmlMarkup) appendDocument(title string, body []string, htmlText *[]string) {
+	if title == "" {
+		title = htmlTitle
+	}
+	*htmlText = append(*htmlText, "<!DOCTYPE html>", `<html lang="en">`, "<head>", `<meta charset="utf-8">`,
+		"<title>"+html.EscapeString(title)+"</title>", "<style>")
+	*htmlText = append(*htmlText, htmlStyle...)
+	*htmlText = append(*htmlText, "</style>", "</head>", `<body id="top">`)
+	*htmlText = append(*htmlText, body...)
+	*htmlText = append(*htmlText, "</body>", "</html>")
+}
+
+func (htmlMarkup) appendTitle(title string, htmlText *[]string) {
+	*htmlText = append(*htmlText, "<h1>"+getHTMLInline(title)+"</h1>")
+}
+
+func (htmlMarkup) appendPackage(packageName string, htmlText *[]string) {
+	*htmlText = append(*htmlText, `<h2 id="`+html.EscapeString(getTopAnchor(packageName))+`"><code>`+html.EscapeString(packageName)+"</code></h2>")
+}
+
+func (htmlMarkup) appendResultsSummary(counts map[string]int, htmlText *[]string) {
+	*htmlText = append(*htmlText, `<p class="summary">`+getResultsSummary(counts, getHTMLResultBadge)+"</p>")
+}
+
+func (htmlMarkup) appendTOC(lines []TOCLine, htmlText *[]string) {
+	*htmlText = append(*htmlText, `<ol class="toc">`)
+	for _, line := range lines {
+		*htmlText = append(*htmlText, `<li><a href="`+html.EscapeString(line.link)+`">`+getHTMLInline(line.caption)+"</a></li>")
+	}
+	*htmlText = append(*htmlText, "</ol>")
+}
+
+func (htmlMarkup) appendSection(section string, htmlText *[]string) {
+	*htmlText = append(*htmlText, "<h3>"+html.EscapeString(section)+"</h3>")
+}
+
+func (htmlMarkup) appendBackground(htmlText *[]string) {
+	*htmlText = append(*htmlText, "<h4>Background</h4>")
+}
+
+func (htmlMarkup) appendFunc(name string, anchor string, depth int, htmlText *[]string) {
+	if depth == 0 {
+		*htmlText = append(*htmlText, "<hr>")
+	}
+	tag := getHTMLHeaderTag(4 + depth)
+	*htmlText = append(*htmlText, "<"+tag+` id="`+html.EscapeString(anchor)+`"><code>`+html.EscapeString(name)+"</code></"+tag+">")
+}
+
+func (htmlMarkup) appendFuncInfo(result TestResult, gitLink string, htmlText *[]string) {
+	var info []string
+	if result.action != "" {
+		info = append(info, getHTMLResultBadge(result.action)+" "+getElapsed(result))
+	}
+	if gitLink != "" {
+		info = append(info, `<a href="`+html.EscapeString(gitLink)+`">view source</a>`)
+	}
+	if info != nil {
+		*htmlText = append(*htmlText, `<p class="info">`+strings.Join(info, " · ")+"</p>")
+	}
+}
+
+func (htmlMarkup) appendTags(tags []string, htmlText *[]string) {
+	*htmlText = append(*htmlText, `<blockquote class="tags">`+html.EscapeString(strings.Join(tags, ", "))+"</blockquote>")
+}
+
+func (htmlMarkup) appendScenario(scenario string, depth int, htmlText *[]string) {
+	tag := getHTMLHeaderTag(3 + depth)
+	*htmlText = append(*htmlText, "<"+tag+` class="scenario">`+getHTMLInline(scenario)+"</"+tag+">")
+}
+
+// appendDescription adds description paragraphs separated by empty lines.
+func (htmlMarkup) appendDescription(description []string, htmlText *[]string) {
+	var paragraph []string
+	for i, line := range description {
+		if line != "" {
+			paragraph = append(paragraph, getHTMLInline(line))
+		}
+		if (line == "" || i == len(description)-1) && paragraph != nil {
+			*htmlText = append(*htmlText, "<p>"+strings.Join(paragraph, "<br>")+"</p>")
+			paragraph = nil
+		}
+	}
+}
+
+// appendSteps adds 'GWT' steps as headers and consecutive indented steps as a list.
+func (htmlMarkup) appendSteps(steps []TestStep, depth int, htmlText *[]string) {
+	isListOpen := false
+	for _, step := range steps {
+		if step.kind.IsGWT() {
+			if isListOpen {
+				*htmlText = append(*htmlText, "</ul>")
+				isListOpen = false
+			}
+			tag := getHTMLHeaderTag(4 + depth)
+			*htmlText = append(*htmlText, "<"+tag+` class="step">`+getStepText(TestStep{step.kind, getHTMLInline(step.comment)}, getHTMLBold)+"</"+tag+">")
+			continue
+		}
+		if !isListOpen {
+			*htmlText = append(*htmlText, `<ul class="steps">`)
+			isListOpen = true
+		}
+		*htmlText = append(*htmlText, `<li class="level-`+strconv.Itoa(int(step.kind))+`">`+getHTMLInline(step.comment)+"</li>")
+	}
+	if isListOpen {
+		*htmlText = append(*htmlText, "</ul>")
+	}
+}
+
+func (htmlMarkup) appendCase(testCase TestCase, depth int, htmlText *[]string) {
+	tag := getHTMLHeaderTag(5 + depth)
+	*htmlText = append(*htmlText, "<"+tag+` class="case">`+html.EscapeString(testCase.name)+"</"+tag+">")
+	if len(testCase.fields) == 0 {
+		return
+	}
+	*htmlText = append(*htmlText, "<table>", "<tr><th>Field</th><th>Value</th></tr>")
+	for _, field := range testCase.fields {
+		*htmlText = append(*htmlText, "<tr><td>"+html.EscapeString(field.name)+"</td><td><code>"+
+			html.EscapeString(field.value)+"</code></td></tr>")
+	}
+	*htmlText = append(*htmlText, "</table>")
+}
+
+func (htmlMarkup) appendFailureOutput(output []string, htmlText *[]string) {
+	*htmlText = append(*htmlText, "<details><summary>Failure output</summary>",
+		"<pre>"+html.EscapeString(strings.Join(output, "\n"))+"</pre>", "</details>")
+}
+
+func (htmlMarkup) appendOutput(output []string, htmlText *[]string) {
+	*htmlText = append(*htmlText, "<p>Output:</p>", "<pre>"+html.EscapeString(strings.Join(output, "\n"))+"</pre>")
+}
+
+func (htmlMarkup) appendFuncEnd(topAnchor string, htmlText *[]string) {
+	*htmlText = append(*htmlText, `<p><a href="#`+html.EscapeString(topAnchor)+`">top</a></p>`)
+}
+
+func (htmlMarkup) appendIndex(items []indexItem, htmlText *[]string) {
+	*htmlText = append(*htmlText, "<h2>Packages</h2>", "<ul>")
+	for _, item := range items {
+		*htmlText = append(*htmlText, `<li><a href="`+html.EscapeString(item.link)+`">`+getHTMLInline(item.caption)+
+			"</a> - "+getTestsCount(item.count)+"</li>")
+	}
+	*htmlText = append(*htmlText, "</ul>")
+}
+
+func getHTMLBold(text string) string {
+	return "<strong>" + text + "</strong>"
+}
+
+func getHTMLResultBadge(action string) string {
+	return `<span class="` + action + `">` + getResultBadge(action) + "</span>"
+}
+
+// getHTMLHeaderTag returns the tag of a header of the level, the deepest level is 6.
+func getHTMLHeaderTag(level int) string {
+	return "h" + strconv.Itoa(min(level, 6))
+}
+
+// getHTMLInline returns the escaped text with `code` spans as <code> elements.
+func getHTMLInline(text string) string {
+	parts := strings.Split(text, "`")
+	if len(parts)%2 == 0 { // unpaired '`'
+		return html.EscapeString(text)
+	}
+	var inline strings.Builder
+	for i, part := range parts {
+		if i%2 == 1 {
+			inline.WriteString("<code>" + html.EscapeString(part) + "</code>")
+		} else {
+			inline.WriteString(html.EscapeString(part))
+		}
+	}
+	return inline.String()
+}
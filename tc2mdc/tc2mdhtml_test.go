@@ -0,0 +1,93 @@
+package tc2mdc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTMLDocument(t *testing.T) {
+	// > Write to HTML
+	// # HTML writer returns a standalone document with CSS and the escaped title
+	// ## GIVEN - testData: "title" = 'A & B', "packageName" = 'pkg'
+	var testData = &TestData{title: "A & B", packageName: "pkg"}
+	writer, err := GetWriter("html")
+	require.Nil(t, err, "must be no error")
+
+	// ## WHEN Write()
+	htmlText := writer.Write(testData)
+
+	// ## THEN - HTML text:
+	// - starts with the doctype, title is escaped
+	require.Equal(t, "<!DOCTYPE html>", htmlText[0])
+	require.Contains(t, htmlText, "<title>A &amp; B</title>")
+	require.Contains(t, htmlText, "<style>")
+	// - ends with the title as header(1), package as header(2) with anchor
+	require.Equal(t, []string{
+		`<body id="top">`,
+		"<h1>A &amp; B</h1>",
+		`<h2 id="pkg"><code>pkg</code></h2>`,
+		"</body>",
+		"</html>",
+	}, htmlText[len(htmlText)-5:])
+	// - extension is '.html'
+	require.Equal(t, ".html", writer.Extension())
+}
+
+func TestHTMLMethod(t *testing.T) {
+	// > Write to HTML
+	// # HTML writer returns a method with headers, tags, steps as lists, cases as tables and the link to the top
+	// ## GIVEN - testData: "packageName" = '' with 1 method 'TestA':
+	var testData = new(TestData)
+	testData.methods = []TestMethod{{
+		name: "TestA",
+		// - "tags" = 'T1', 'T2', "scenario" = 'Returns `nil` on <empty>'
+		tags:     []string{"T1", "T2"},
+		scenario: "Returns `nil` on <empty>",
+		// - "description" = 'Line 1', 'Line 2', '', 'Line 3'
+		description: []string{"Line 1", "Line 2", "", "Line 3"},
+		// - "steps": 'GWT' 'WHEN act', common 'Step1', indented 'Step2', 'GWT' 'THEN check'
+		steps: []TestStep{{GWT, "WHEN act"}, {Common, "Step1"}, {Indented, "Step2"}, {GWT, "THEN check"}},
+		// - "cases": 'c1' with field {'in', '"<a>"'}
+		cases: []TestCase{{name: "c1", fields: []TestField{{"in", `"<a>"`}}}},
+		// - "result" = 'fail' with output 'x < y'
+		result: TestResult{action: ActionFail, elapsed: 0.5, output: []string{"x < y"}},
+	}}
+	writer, _ := GetWriter("html")
+
+	// ## WHEN Write()
+	htmlText := writer.Write(testData)
+
+	// ## THEN - HTML body is:
+	start := 0
+	for htmlText[start] != `<body id="top">` {
+		start++
+	}
+	require.Equal(t, []string{
+		`<p class="summary"><span class="pass">✅ PASS</span> 0 · <span class="fail">❌ FAIL</span> 1 · <span class="skip">⏭️ SKIP</span> 0</p>`,
+		"<hr>",
+		`<h4 id="testa"><code>TestA</code></h4>`,
+		`<p class="info"><span class="fail">❌ FAIL</span> 0.50s</p>`,
+		`<blockquote class="tags">T1, T2</blockquote>`,
+		`<h3 class="scenario">Returns <code>nil</code> on &lt;empty&gt;</h3>`,
+		"<p>Line 1<br>Line 2</p>",
+		"<p>Line 3</p>",
+		`<h4 class="step">WHEN act</h4>`,
+		`<ul class="steps">`,
+		`<li class="level-1">Step1</li>`,
+		`<li class="level-2">Step2</li>`,
+		"</ul>",
+		`<h4 class="step">THEN check</h4>`,
+		`<h5 class="case">c1</h5>`,
+		"<table>",
+		"<tr><th>Field</th><th>Value</th></tr>",
+		"<tr><td>in</td><td><code>&#34;&lt;a&gt;&#34;</code></td></tr>",
+		"</table>",
+		"<details><summary>Failure output</summary>",
+		"<pre>x &lt; y</pre>",
+		"</details>",
+		`<p><a href="#top">top</a></p>`,
+		"</body>",
+		"</html>",
+	}, htmlText[start+1:])
+}
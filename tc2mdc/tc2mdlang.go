@@ -0,0 +1,472 @@
+package tc2mdc
+
+import (
+	"go/ast"
+	"go/token"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// frontend is the line-based parsing of test files of a language other than Go. Markers are the same
+// as in Go with the comment of the language instead of "//", e.g. "# ## WHEN act" in Python.
+type frontend struct {
+	extensions   []string
+	testFiles    *regexp.Regexp // slash-separated paths of test files found in directories
+	comment      string         // one line comment
+	docComment   string         // one line doc comment parsed as a one line comment, e.g. "///"
+	blocks       bool           // "/* */" block comments
+	indented     bool           // bodies are indented blocks, otherwise they are in braces
+	quotes       []string       // quotes of one line string literals to skip braces in them
+	textBlocks   []string       // quotes of string literals continued on next lines, e.g. """ in Java
+	chars        bool           // char literals in single quotes besides lifetimes like "'a" in Rust
+	rePackage    *regexp.Regexp // package declaration with the "name" group, nil to name the package by the file
+	reAttribute  *regexp.Regexp // annotation, decorator or attribute line
+	reAnnotation *regexp.Regexp // annotation required before a test, nil if a declaration is enough
+	reTest       *regexp.Regexp // test declaration with the "name" group
+	reGroup      *regexp.Regexp // group of tests with the "name" group, e.g. "describe('name', ...)"
+}
+
+// reEach is the optional table of parameterized tests in Jest before the name of a test or a group,
+// e.g. "it.each([[1, 2]])('adds %i', ...)" or "test.each`a | b`('adds $a', ...)" on one line
+const reEach = "(?:\\.each\\s*(?:\\(.*?\\)|`[^`]*`))?"
+
+// Frontends of languages by their names
+var frontends = map[string]*frontend{
+	"java": {
+		extensions:   []string{".java"},
+		testFiles:    regexp.MustCompile(`(^|/)(Test\w*|\w*Tests?|\w*IT)\.java$`),
+		comment:      OLC,
+		blocks:       true,
+		quotes:       []string{`"`, `'`},
+		textBlocks:   []string{`"""`},
+		rePackage:    regexp.MustCompile(`^\s*package\s+(?P<name>[\w.]+)`),
+		reAttribute:  regexp.MustCompile(`^\s*@`),
+		reAnnotation: regexp.MustCompile(`@(Test|ParameterizedTest|RepeatedTest|TestFactory|TestTemplate)\b`),
+		reTest:       regexp.MustCompile(`^\s*(?:@\w+(?:\(.*?\))?\s+)*(?:\w+\s+)*[\w<>\[\],.?]+\s+(?P<name>\w+)\s*\(`),
+	},
+	"kotlin": {
+		extensions:   []string{".kt"},
+		testFiles:    regexp.MustCompile(`(^|/)(Test\w*|\w*Tests?|\w*IT)\.kt$`),
+		comment:      OLC,
+		blocks:       true,
+		quotes:       []string{`"`, `'`},
+		textBlocks:   []string{`"""`},
+		rePackage:    regexp.MustCompile(`^\s*package\s+(?P<name>[\w.]+)`),
+		reAttribute:  regexp.MustCompile(`^\s*@`),
+		reAnnotation: regexp.MustCompile(`@(Test|ParameterizedTest|RepeatedTest|TestFactory|TestTemplate)\b`),
+		reTest:       regexp.MustCompile("^\\s*(?:@\\w+(?:\\(.*?\\))?\\s+)*(?:\\w+\\s+)*fun\\s+`?(?P<name>[^`(]+?)`?\\s*\\("),
+	},
+	"python": {
+		extensions:  []string{".py"},
+		testFiles:   regexp.MustCompile(`(^|/)(test_\w*|\w*_test)\.py$`),
+		comment:     "#",
+		indented:    true,
+		quotes:      []string{`"`, `'`},
+		textBlocks:  []string{`"""`, `'''`},
+		reAttribute: regexp.MustCompile(`^\s*@`),
+		reTest:      regexp.MustCompile(`^\s*(?:async\s+)?def\s+(?P<name>test\w*)\s*\(`),
+	},
+	"typescript": {
+		extensions:  []string{".ts", ".tsx"},
+		testFiles:   regexp.MustCompile(`\.(test|spec)\.tsx?$`),
+		comment:     OLC,
+		blocks:      true,
+		quotes:      []string{`"`, `'`},
+		textBlocks:  []string{"`"},
+		reAttribute: regexp.MustCompile(`^\s*@`),
+		reTest:      regexp.MustCompile("^\\s*(?:it|test)(?:\\.\\w+)*" + reEach + "\\s*\\(\\s*[\"'`](?P<name>[^\"'`]*)[\"'`]"),
+		reGroup:     regexp.MustCompile("^\\s*describe(?:\\.\\w+)*" + reEach + "\\s*\\(\\s*[\"'`](?P<name>[^\"'`]*)[\"'`]"),
+	},
+	"rust": {
+		extensions:   []string{".rs"},
+		testFiles:    regexp.MustCompile(`(^|/)(tests/[^/]+|tests|\w+_tests?)\.rs$`),
+		comment:      OLC,
+		docComment:   "///",
+		blocks:       true,
+		quotes:       []string{`"`}, // a quote is also a lifetime, e.g. "'a"
+		chars:        true,
+		reAttribute:  regexp.MustCompile(`^\s*#\[`),
+		reAnnotation: regexp.MustCompile(`#\[(\w+::)*test\]`),
+		reTest:       regexp.MustCompile(`^\s*(?:pub(?:\(\w+\))?\s+)?(?:async\s+)?fn\s+(?P<name>\w+)\s*[(<]`),
+	},
+}
+
+// Languages returns names of languages parsed besides Go, sorted.
+func Languages() []string {
+	var languages []string
+	for language := range frontends {
+		languages = append(languages, language)
+	}
+	sort.Strings(languages)
+	return languages
+}
+
+// IsTestFile checks the path is a test file by its name: "*_test.go" in Go, "*Test.java" and "*Test.kt"
+// in Java and Kotlin, "test_*.py" in Python, "*.test.ts" and "*.spec.ts" in TypeScript,
+// "tests.rs", "*_test.rs" or any file of a "tests" directory in Rust.
+func IsTestFile(path string) bool {
+	path = filepath.ToSlash(path)
+	if strings.HasSuffix(path, "_test.go") {
+		return true
+	}
+	lang := getFrontend(path)
+	return lang != nil && lang.testFiles.MatchString(path)
+}
+
+// getFrontend returns the frontend of the file extension, 'nil' for Go and unknown extensions.
+func getFrontend(filename string) *frontend {
+	ext := filepath.Ext(filename)
+	for _, lang := range frontends {
+		if containsString(lang.extensions, ext) {
+			return lang
+		}
+	}
+	return nil
+}
+
+// parseSource parses a test file of a language by its extension, Go is the default one.
+func parseSource(filename string, src []byte, grammar *markerGrammar) (*TestData, error) {
+	if lang := getFrontend(filename); lang != nil {
+		return lang.parse(filename, src, grammar)
+	}
+	return parseGoFile(filename, src, grammar)
+}
+
+// langComment is a one line or block comment of a source.
+type langComment struct {
+	offset int    // offset of the comment in the source
+	column int    // 1-based column in bytes
+	text   string // the comment with its start, e.g. "# ## WHEN act"
+}
+
+// langScope is an open test or group of tests: a block in braces or an indented block.
+type langScope struct {
+	method int // index of the test method, -1 for a group
+	name   string
+	depth  int  // depth of braces or indentation of the declaration
+	opened bool // the body is started
+}
+
+// langParser is the state of the line-based parsing of a file.
+type langParser struct {
+	lang     *frontend
+	filename string
+	grammar  *markerGrammar
+	fset     *token.FileSet
+	file     *token.File
+	data     *TestData
+	doc      []langComment // comments directly above a declaration
+	scopes   []*langScope
+	depth    int    // depth of braces, of all brackets in indented languages
+	quote    string // quote of a string literal continued on the next line
+	isTest   bool   // a required annotation is found above a declaration
+	errs     ParseErrors
+}
+
+// parse finds tests by their declarations, markers of a test are comments of its body and comments directly
+// above it (other lines of them are the description). A test body ends with its closing brace or
+// with a line which is not indented deeper than the declaration.
+func (lang *frontend) parse(filename string, src []byte, grammar *markerGrammar) (*TestData, error) {
+	if len(src) == 0 {
+		return nil, ParseErrors{{File: filename, Reason: "empty input"}}
+	}
+	parser := &langParser{lang: lang, filename: filename, grammar: grammar, fset: token.NewFileSet(),
+		data: &TestData{packageName: getFilePackage(filename)}}
+	parser.file = parser.fset.AddFile(filename, -1, len(src))
+	parser.file.SetLinesForContent(src)
+
+	lines := strings.Split(string(src), "\n")
+	offset := 0
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimLeft(line, " \t")
+		indentation := len(line) - len(trimmed)
+		comment := langComment{offset + indentation, indentation + 1, trimmed}
+		switch {
+		case parser.quote != "":
+			{
+				parser.parseCode(line, i+1, indentation)
+			}
+		case lang.blocks && strings.HasPrefix(trimmed, BCStart):
+			{
+				for !strings.Contains(lines[i], BCEnd) && i+1 < len(lines) {
+					offset += len(lines[i]) + 1
+					i++
+					comment.text += "\n" + lines[i]
+				}
+				parser.parseComment(comment)
+			}
+		case strings.HasPrefix(trimmed, lang.comment):
+			{
+				parser.parseComment(comment)
+			}
+		case trimmed == "":
+			{
+				parser.doc = nil
+			}
+		default:
+			{
+				parser.parseCode(line, i+1, indentation)
+			}
+		}
+		offset += len(lines[i]) + 1
+	}
+	return parser.data, parser.errs.err()
+}
+
+// getFilePackage returns the name of the file without extensions as the package, e.g. "calc" of "calc.test.ts".
+func getFilePackage(filename string) string {
+	name := filepath.Base(filename)
+	if index := strings.Index(name, "."); index > 0 {
+		return name[:index]
+	}
+	return name
+}
+
+// parseComment adds markers of a comment to the current test, a comment out of tests is a file-level one
+// and a part of the doc comment of the next test.
+func (parser *langParser) parseComment(comment langComment) {
+	parser.closeScopes(comment.column - 1)
+	method := parser.getMethod()
+	if method == nil {
+		parser.doc = append(parser.doc, comment)
+		parseTitle(parser.toGoComment(comment.text), parser.grammar, parser.data)
+		return
+	}
+	if strings.HasPrefix(comment.text, BCStart) {
+		parseBlockComment(comment.text, parser.grammar, method)
+	} else {
+		parseOneLineComment(parser.toGoComment(comment.text), parser.grammar, method)
+	}
+	parser.addMarkerErrors(comment, false)
+}
+
+// parseCode opens and closes tests and groups by declarations and braces (or indentation) of a code line,
+// a line of a string literal continued from previous lines has no declarations.
+func (parser *langParser) parseCode(line string, lineNumber int, indentation int) {
+	lang := parser.lang
+	parser.closeScopes(indentation)
+	if parser.quote == "" {
+		parser.parseDeclaration(line, lineNumber, indentation)
+	}
+
+	maxDepth := parser.countBraces(line)
+	if lang.indented {
+		if len(parser.scopes) > 0 && !parser.isContinued() && strings.HasSuffix(strings.TrimSpace(line), ":") {
+			parser.scopes[len(parser.scopes)-1].opened = true
+		}
+		return
+	}
+	for _, scope := range parser.scopes {
+		if maxDepth > scope.depth {
+			scope.opened = true
+		}
+	}
+	parser.closeScopes(indentation)
+}
+
+// parseDeclaration opens a test or a group of tests by its declaration and tracks annotations above it.
+func (parser *langParser) parseDeclaration(line string, lineNumber int, indentation int) {
+	lang := parser.lang
+	if lang.rePackage != nil && parser.data.methods == nil {
+		if name := getMatchesMap(lang.rePackage, line)["name"]; name != "" {
+			parser.data.packageName = name
+		}
+	}
+	if lang.reAnnotation != nil && lang.reAnnotation.MatchString(line) {
+		parser.isTest = true
+	}
+
+	depth := parser.depth
+	if lang.indented {
+		depth = indentation
+	}
+	switch name := getMatchesMap(lang.reTest, line)["name"]; {
+	case name != "" && parser.getMethod() == nil && (lang.reAnnotation == nil || parser.isTest):
+		{
+			parser.openTest(name, lineNumber, depth)
+		}
+	case lang.reGroup != nil && lang.reGroup.MatchString(line):
+		{
+			name := getMatchesMap(lang.reGroup, line)["name"]
+			parser.scopes = append(parser.scopes, &langScope{method: -1, name: name, depth: depth})
+			parser.doc = nil
+		}
+	case !lang.reAttribute.MatchString(line):
+		{
+			parser.doc = nil
+			parser.isTest = false
+		}
+	}
+}
+
+// openTest adds the test with markers of the doc comment above it, the name of a test in a group
+// is "Group/Name".
+func (parser *langParser) openTest(name string, lineNumber int, depth int) {
+	var names []string
+	for _, scope := range parser.scopes {
+		names = append(names, scope.name)
+	}
+	method := TestMethod{name: strings.Join(append(names, name), "/"), file: parser.filename, line: lineNumber}
+	var doc ast.CommentGroup
+	for _, comment := range parser.doc {
+		text := comment.text
+		if !strings.HasPrefix(text, BCStart) {
+			text = parser.toGoComment(text)
+		}
+		doc.List = append(doc.List, &ast.Comment{Text: text})
+		parser.addMarkerErrors(comment, true)
+	}
+	parseDocComment(&doc, parser.grammar, &method)
+
+	parser.data.methods = append(parser.data.methods, method)
+	parser.scopes = append(parser.scopes, &langScope{method: len(parser.data.methods) - 1, name: name, depth: depth})
+	parser.doc = nil
+	parser.isTest = false
+}
+
+// closeScopes closes started tests and groups which end before the line: by the depth of braces
+// or by the indentation of the line. The indentation of a line continuing open brackets or a string
+// literal of previous lines is not a dedent.
+func (parser *langParser) closeScopes(indentation int) {
+	depth := parser.depth
+	if parser.lang.indented {
+		if parser.isContinued() {
+			return
+		}
+		depth = indentation
+	}
+	for len(parser.scopes) > 0 {
+		scope := parser.scopes[len(parser.scopes)-1]
+		if !scope.opened || depth > scope.depth {
+			return
+		}
+		parser.scopes = parser.scopes[:len(parser.scopes)-1]
+	}
+}
+
+// isContinued checks the next line continues open brackets of an indented language or a string literal.
+func (parser *langParser) isContinued() bool {
+	return parser.quote != "" || (parser.lang.indented && parser.depth > 0)
+}
+
+// getMethod returns the innermost open test, 'nil' out of tests.
+func (parser *langParser) getMethod() *TestMethod {
+	for i := len(parser.scopes) - 1; i >= 0; i-- {
+		if parser.scopes[i].method >= 0 {
+			return &parser.data.methods[parser.scopes[i].method]
+		}
+	}
+	return nil
+}
+
+// splitComment returns the comment start of a one line comment and the rest of it.
+func (parser *langParser) splitComment(text string) (string, string) {
+	for _, start := range []string{parser.lang.docComment, parser.lang.comment} {
+		if start != "" && strings.HasPrefix(text, start) {
+			return start, text[len(start):]
+		}
+	}
+	return "", text
+}
+
+// toGoComment replaces the comment start of a one line comment with "//" to parse it as a Go comment.
+func (parser *langParser) toGoComment(text string) string {
+	_, rest := parser.splitComment(text)
+	return OLC + rest
+}
+
+// addMarkerErrors adds problems of markers of the comment, see appendMarkerErrors().
+func (parser *langParser) addMarkerErrors(comment langComment, isDoc bool) {
+	if strings.HasPrefix(comment.text, BCStart) {
+		appendMarkerErrors(&ast.Comment{Slash: parser.file.Pos(comment.offset), Text: comment.text}, isDoc,
+			parser.filename, parser.fset, parser.grammar, &parser.errs)
+		return
+	}
+	start, rest := parser.splitComment(comment.text)
+	prefix := parser.grammar.prefix[len(OLC):] // the rest of the prefix of the grammar, e.g. ":" of "//:"
+	if !strings.HasPrefix(rest, prefix) {
+		return
+	}
+	if marker, offset, reason := getMarkerError(rest[len(prefix):], parser.grammar, !isDoc); reason != "" {
+		parser.errs = append(parser.errs, &ParseError{parser.filename, parser.file.Line(parser.file.Pos(comment.offset)),
+			comment.column + len(start) + len(prefix) + offset, marker, reason})
+	}
+}
+
+// countBraces updates the depth of braces (of all brackets in indented languages) and the quote of a string
+// literal continued on the next line by the line, and returns the max depth within it. Braces in string literals
+// and comments are skipped, a one line string literal ends with the line.
+func (parser *langParser) countBraces(line string) int {
+	lang := parser.lang
+	opening, closing := "{", "}"
+	if lang.indented {
+		opening, closing = "([{", ")]}"
+	}
+	maxDepth := parser.depth
+	for i := 0; i < len(line); i++ {
+		switch quote := getQuote(line[i:], lang); {
+		case parser.quote != "":
+			{
+				if line[i] == '\\' {
+					i++
+				} else if strings.HasPrefix(line[i:], parser.quote) {
+					i += len(parser.quote) - 1
+					parser.quote = ""
+				}
+			}
+		case quote != "":
+			{
+				parser.quote = quote
+				i += len(quote) - 1
+			}
+		case lang.chars && reChar.MatchString(line[i:]):
+			{
+				i += len(reChar.FindString(line[i:])) - 1
+			}
+		case strings.HasPrefix(line[i:], lang.comment):
+			{
+				return maxDepth
+			}
+		case lang.blocks && strings.HasPrefix(line[i:], BCStart):
+			{
+				end := strings.Index(line[i:], BCEnd)
+				if end < 0 {
+					return maxDepth
+				}
+				i += end + len(BCEnd) - 1
+			}
+		case strings.IndexByte(opening, line[i]) >= 0:
+			{
+				parser.depth++
+				if parser.depth > maxDepth {
+					maxDepth = parser.depth
+				}
+			}
+		case strings.IndexByte(closing, line[i]) >= 0:
+			{
+				parser.depth--
+			}
+		}
+	}
+	if !containsString(lang.textBlocks, parser.quote) {
+		parser.quote = ""
+	}
+	return maxDepth
+}
+
+// Char literal with one char or an escape sequence, e.g. '{', '\” or '\u{7B}', a lifetime has no closing quote
+var reChar = regexp.MustCompile(`^'(\\(u\{[[:xdigit:]]+\}|x[[:xdigit:]]{2}|.)|[^\\'])'`)
+
+// getQuote returns the quote of a string literal the text starts with, text blocks are checked first
+// as their quotes start with quotes of one line literals.
+func getQuote(text string, lang *frontend) string {
+	for _, quote := range append(append([]string(nil), lang.textBlocks...), lang.quotes...) {
+		if strings.HasPrefix(text, quote) {
+			return quote
+		}
+	}
+	return ""
+}
@@ -0,0 +1,327 @@
+package tc2mdc
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLangJava(t *testing.T) {
+	// > Languages
+	// # ParseFile() of a ".java" file finds "@Test" methods with markers of their bodies and javadoc
+	// ## GIVEN Input is
+	var input = strings.Join([]string{
+		"// #! Calculator",
+		"package com.example.calc;",
+		"",
+		"class CalcTest {",
+		// - javadoc with the scenario and the description
+		"    /**",
+		"     * # Adds numbers",
+		"     * Sum of two numbers.",
+		"     */",
+		"    @Test",
+		"    @DisplayName(\"adds\")",
+		"    void testAdd() {",
+		"        // ## GIVEN numbers",
+		"        if (a > 0) { a = '}'; }",
+		"        // ## WHEN add",
+		"        /* - with \"{\" in a block */",
+		"    }",
+		"",
+		// - helper method with markers is skipped
+		"    void helper() {",
+		"        // ## THEN skipped",
+		"    }",
+		"",
+		"    @ParameterizedTest void testNeg(int a) {",
+		"        //## THEN malformed",
+		"    }",
+		"}",
+	}, "\n")
+
+	// ## WHEN ParseFile() of 'CalcTest.java'
+	data, err := ParseFile("CalcTest.java", []byte(input))
+	// ## THEN the title and the package are of the file
+	require.NotNil(t, data, "must be parsed")
+	require.Equal(t, "Calculator", data.Title())
+	require.Equal(t, "com.example.calc", data.PackageName())
+	// ## AND methods are 'testAdd' and 'testNeg' with markers
+	methods := data.Methods()
+	require.Equal(t, 2, len(methods))
+	require.Equal(t, "testAdd", methods[0].Name())
+	require.Equal(t, 11, methods[0].Line())
+	require.Equal(t, "Adds numbers", methods[0].Scenario())
+	require.Equal(t, []string{"Sum of two numbers."}, methods[0].Description())
+	require.Equal(t, []TestStep{{Given, "numbers"}, {When, "add"}, {Common, "with \"{\" in a block"}}, methods[0].Steps())
+	require.Equal(t, "testNeg", methods[1].Name())
+	require.Nil(t, methods[1].Steps(), "steps must be nil")
+	// ## AND the malformed marker is the error
+	require.EqualError(t, err, `CalcTest.java:23:11: no space between "//" and marker "##"`)
+}
+
+func TestLangKotlinAndRust(t *testing.T) {
+	// > Languages
+	// # ParseFile() finds Kotlin "@Test fun" and Rust "#[test] fn" tests
+	// ## GIVEN Kotlin input with a name in backticks
+	var kotlin = strings.Join([]string{
+		"package calc",
+		"",
+		"class CalcTest {",
+		"    // # Adds",
+		"    @Test",
+		"    fun `adds two numbers`() {",
+		"        // ## WHEN add",
+		"    }",
+		"}",
+	}, "\n")
+	// ## AND Rust input with doc comments and a helper without "#[test]"
+	var rust = strings.Join([]string{
+		"#[cfg(test)]",
+		"mod tests {",
+		"    /// # Adds",
+		"    #[test]",
+		"    #[should_panic]",
+		"    fn adds<'a>() {",
+		"        let s: &'a str = \"}\";",
+		"        // ## WHEN add",
+		"    }",
+		"",
+		"    fn helper() {",
+		"        // ## THEN skipped",
+		"    }",
+		"}",
+	}, "\n")
+
+	// ## WHEN ParseFile() of 'CalcTest.kt' and 'calc_tests.rs'
+	kotlinData, kotlinErr := ParseFile("CalcTest.kt", []byte(kotlin))
+	rustData, rustErr := ParseFile("src/calc_tests.rs", []byte(rust))
+	// ## THEN each file has one test with the scenario and the step
+	require.Nil(t, kotlinErr, "must be no error")
+	require.Nil(t, rustErr, "must be no error")
+	for _, data := range []*TestData{kotlinData, rustData} {
+		require.Equal(t, 1, len(data.Methods()))
+		require.Equal(t, "Adds", data.Methods()[0].Scenario())
+		require.Equal(t, []TestStep{{When, "add"}}, data.Methods()[0].Steps())
+	}
+	// - names are 'adds two numbers' and 'adds'
+	require.Equal(t, "adds two numbers", kotlinData.Methods()[0].Name())
+	require.Equal(t, "adds", rustData.Methods()[0].Name())
+	// - packages are 'calc' of the declaration and 'calc_tests' of the file name
+	require.Equal(t, "calc", kotlinData.PackageName())
+	require.Equal(t, "calc_tests", rustData.PackageName())
+}
+
+func TestLangRustChars(t *testing.T) {
+	// > Languages
+	// # ParseFile() of a ".rs" file skips braces in char literals and tells them from lifetimes
+	// ## GIVEN Rust input with '{', '}' and '\'' chars and a lifetime in a test
+	var input = strings.Join([]string{
+		"#[test]",
+		"fn adds<'a>() {",
+		"    let c = '{';",
+		"    let s: &'a str = \"x\";",
+		"    let q = ['\\'', '}', '}'];",
+		"    // ## WHEN add",
+		"}",
+		"",
+		"fn helper() {",
+		"    // ## THEN skipped",
+		"}",
+		"",
+		"// # Async",
+		"#[tokio::test]",
+		"async fn asyncy() {",
+		"}",
+	}, "\n")
+
+	// ## WHEN ParseFile() of 'calc_tests.rs'
+	data, err := ParseFile("calc_tests.rs", []byte(input))
+	// ## THEN tests are 'adds' with its step only and 'asyncy' with its scenario
+	require.Nil(t, err, "must be no error")
+	methods := data.Methods()
+	require.Equal(t, 2, len(methods))
+	require.Equal(t, "adds", methods[0].Name())
+	require.Equal(t, "", methods[0].Scenario())
+	require.Equal(t, []TestStep{{When, "add"}}, methods[0].Steps())
+	require.Equal(t, "asyncy", methods[1].Name())
+	require.Equal(t, "Async", methods[1].Scenario())
+}
+
+func TestLangPython(t *testing.T) {
+	// > Languages
+	// # ParseFile() of a ".py" file finds "test_" funcs by indentation with "#" comments as markers
+	// ## GIVEN Input is
+	var input = strings.Join([]string{
+		"# #! Calculator",
+		"import pytest",
+		"",
+		"# # Adds numbers",
+		"# Sum of two numbers.",
+		"@pytest.mark.parametrize(\"a\", [1, 2])",
+		"def test_add(",
+		"    a,",
+		"):",
+		"    # > math, sum",
+		"    # ## GIVEN a number",
+		"",
+		"    # - one",
+		"    assert a > 0",
+		"# ## THEN out of the test",
+		"",
+		"class TestCalc:",
+		"    def test_sub(self):",
+		"        #  ## WHEN extra space",
+		"        pass",
+		"",
+		"    def helper(self):",
+		"        # ## THEN skipped",
+	}, "\n")
+
+	// ## WHEN ParseFile() of 'test_calc.py'
+	data, err := ParseFile("test_calc.py", []byte(input))
+	// ## THEN the title and the package of the file name are set
+	require.NotNil(t, data, "must be parsed")
+	require.Equal(t, "Calculator", data.Title())
+	require.Equal(t, "test_calc", data.PackageName())
+	// ## AND methods are 'test_add' with markers and 'test_sub' without them
+	methods := data.Methods()
+	require.Equal(t, 2, len(methods))
+	require.Equal(t, "test_add", methods[0].Name())
+	require.Equal(t, "Adds numbers", methods[0].Scenario())
+	require.Equal(t, []string{"Sum of two numbers."}, methods[0].Description())
+	require.Equal(t, []string{"math", "sum"}, methods[0].Tags())
+	require.Equal(t, []TestStep{{Given, "a number"}, {Common, "one"}}, methods[0].Steps())
+	require.Equal(t, "test_sub", methods[1].Name())
+	require.Equal(t, 18, methods[1].Line())
+	require.Nil(t, methods[1].Steps(), "steps must be nil")
+	// ## AND the malformed marker is the error with the column after "#"
+	require.EqualError(t, err, `test_calc.py:19:12: extra space before marker "##"`)
+}
+
+func TestLangTypeScript(t *testing.T) {
+	// > Languages
+	// # ParseFile() of a ".ts" file finds "it" and "test" calls named by "describe" groups, also with ".each" tables
+	// ## GIVEN Input is
+	var input = strings.Join([]string{
+		"describe('Calculator', () => {",
+		"  // # Adds numbers",
+		"  it('adds', () => {",
+		"    const s = `}`;",
+		"    // ## WHEN add",
+		"  });",
+		"});",
+		"",
+		"test(\"subtracts\", async () => {",
+		"  // ## THEN subtract",
+		"});",
+		"",
+		"describe.each([[1], [2]])('Table %i', (n) => {",
+		"  it.each([[1, 2]])('adds %i', (a, b) => {",
+		"  });",
+		"  test.only.each`a | b`('adds $a', ({a}) => {",
+		"  });",
+		"});",
+	}, "\n")
+
+	// ## WHEN ParseFile() of 'calc.test.ts'
+	data, err := ParseFile("calc.test.ts", []byte(input))
+	// ## THEN tests are 'Calculator/adds' and 'subtracts' in the package 'calc'
+	require.Nil(t, err, "must be no error")
+	require.Equal(t, "calc", data.PackageName())
+	methods := data.Methods()
+	require.Equal(t, 4, len(methods))
+	require.Equal(t, "Calculator/adds", methods[0].Name())
+	require.Equal(t, "Adds numbers", methods[0].Scenario())
+	require.Equal(t, []TestStep{{When, "add"}}, methods[0].Steps())
+	require.Equal(t, "subtracts", methods[1].Name())
+	require.Equal(t, []TestStep{{Then, "subtract"}}, methods[1].Steps())
+	// - tests of ".each" tables on one line are named by the name after the table
+	require.Equal(t, "Table %i/adds %i", methods[2].Name())
+	require.Equal(t, "Table %i/adds $a", methods[3].Name())
+}
+
+func TestLangContinuedLines(t *testing.T) {
+	// > Languages
+	// # ParseFile() skips dedents and braces of lines continuing brackets and multi-line string literals
+	// ## GIVEN Python input with a triple-quoted string and brackets continued at column 0
+	var python = strings.Join([]string{
+		"def test_query():",
+		"    # ## GIVEN a query",
+		`    sql = """`,
+		"SELECT 1",
+		`"""`,
+		"    rows = run(sql, [",
+		"1, 2,",
+		"])",
+		"    # ## THEN rows are found",
+		"",
+		"# ## THEN out of the test",
+	}, "\n")
+	// ## AND TypeScript input with braces in a multi-line template literal
+	var typescript = strings.Join([]string{
+		"it('renders', () => {",
+		"  const html = `<p>",
+		"  }}`;",
+		"  // ## THEN rendered",
+		"});",
+	}, "\n")
+	// ## AND Java input with braces and quotes in a text block
+	var java = strings.Join([]string{
+		"class QueryTest {",
+		"    @Test",
+		"    void testQuery() {",
+		`        String json = """`,
+		`            {"a": "}"`,
+		`            """;`,
+		"        // ## THEN parsed",
+		"    }",
+		"",
+		"    @Test",
+		"    void testNext() {",
+		"    }",
+		"}",
+	}, "\n")
+
+	// ## WHEN ParseFile() of 'test_query.py', 'query.test.ts' and 'QueryTest.java'
+	pythonData, pythonErr := ParseFile("test_query.py", []byte(python))
+	typescriptData, typescriptErr := ParseFile("query.test.ts", []byte(typescript))
+	javaData, javaErr := ParseFile("QueryTest.java", []byte(java))
+	// ## THEN markers after the continued lines are steps of the tests
+	require.Nil(t, pythonErr, "must be no error")
+	require.Nil(t, typescriptErr, "must be no error")
+	require.Nil(t, javaErr, "must be no error")
+	require.Equal(t, 1, len(pythonData.Methods()))
+	require.Equal(t, []TestStep{{Given, "a query"}, {Then, "rows are found"}}, pythonData.Methods()[0].Steps())
+	require.Equal(t, 1, len(typescriptData.Methods()))
+	require.Equal(t, []TestStep{{Then, "rendered"}}, typescriptData.Methods()[0].Steps())
+	// ## AND the Java test after the text block is found
+	require.Equal(t, 2, len(javaData.Methods()))
+	require.Equal(t, []TestStep{{Then, "parsed"}}, javaData.Methods()[0].Steps())
+	require.Equal(t, "testNext", javaData.Methods()[1].Name())
+}
+
+func TestIsTestFile(t *testing.T) {
+	// > Languages
+	// # IsTestFile() checks names of test files of each language
+	// ## GIVEN paths and expected results:
+	var paths = map[string]bool{
+		// - Go: 'a_test.go' but not 'a.go'
+		"pkg/a_test.go": true, "pkg/a.go": false,
+		// - Java and Kotlin: 'CalcTest.java', 'TestCalc.kt' but not 'Calc.java'
+		"src/CalcTest.java": true, "src/TestCalc.kt": true, "src/Calc.java": false,
+		// - Python: 'test_calc.py', 'calc_test.py' but not 'calc.py'
+		"test_calc.py": true, "calc_test.py": true, "calc.py": false,
+		// - TypeScript: 'calc.test.ts', 'calc.spec.tsx' but not 'calc.ts'
+		"calc.test.ts": true, "calc.spec.tsx": true, "calc.ts": false,
+		// - Rust: 'tests/calc.rs', 'src/tests.rs' but not 'src/calc.rs'
+		"tests/calc.rs": true, "src/tests.rs": true, "src/calc.rs": false,
+	}
+
+	for path, expected := range paths {
+		// ## WHEN IsTestFile()
+		// ## THEN the result is as expected
+		require.Equal(t, expected, IsTestFile(path), path)
+	}
+}
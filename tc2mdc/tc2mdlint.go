@@ -0,0 +1,97 @@
+package tc2mdc
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+)
+
+// LintIssue is a problem of the documentation of a test found by Lint().
+type LintIssue struct {
+	file    string
+	line    int
+	message string
+}
+
+// File returns the path of the test file.
+func (issue LintIssue) File() string {
+	return issue.file
+}
+
+// Line returns the source line of the issue.
+func (issue LintIssue) Line() int {
+	return issue.line
+}
+
+// Message returns the description of the issue.
+func (issue LintIssue) Message() string {
+	return issue.message
+}
+
+// String returns the issue as "file:line: message".
+func (issue LintIssue) String() string {
+	return issue.file + ":" + strconv.Itoa(issue.line) + ": " + issue.message
+}
+
+// Lint returns issues of a test file ordered by line: tests without a scenario or without 'GWT' steps
+// (benchmarks, fuzz tests and examples may have none), steps out of order (see ValidateSteps()),
+// unknown markers like "### THEN" and near-miss markers like "//## WHEN" or "// ##WHEN" which are
+// not parsed (ParseErrors of ParseFile()).
+// Syntax errors are returned as the error. Markers of other grammars are checked by Grammar.Lint().
+func Lint(filename string, src []byte) ([]LintIssue, error) {
+	return lint(filename, src, defaultGrammar)
+}
+
+func lint(filename string, src []byte, grammar *markerGrammar) ([]LintIssue, error) {
+	testData, err := parseSource(filename, src, grammar)
+	if testData == nil {
+		return nil, err
+	}
+	var issues []LintIssue
+	for _, method := range testData.methods {
+		if method.kind != TestFunc {
+			appendStepIssues(method, &issues)
+			continue
+		}
+		if method.scenario == "" {
+			issues = append(issues, LintIssue{filename, method.line, method.name + ": test without scenario"})
+		}
+		if !hasGWTSteps(method) {
+			issues = append(issues, LintIssue{filename, method.line, method.name + ": test without GIVEN/WHEN/THEN steps"})
+		}
+		appendStepIssues(method, &issues)
+	}
+	var markerErrors ParseErrors
+	if errors.As(err, &markerErrors) {
+		for _, markerError := range markerErrors {
+			issues = append(issues, LintIssue{filename, markerError.Line, markerError.Reason})
+		}
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool { return issues[i].line < issues[j].line })
+	return issues, nil
+}
+
+// hasGWTSteps checks the method or any of its subtests has a 'GWT' step.
+func hasGWTSteps(method TestMethod) bool {
+	for _, step := range method.steps {
+		if step.kind.IsGWT() {
+			return true
+		}
+	}
+	for _, subtest := range method.subtests {
+		if hasGWTSteps(subtest) {
+			return true
+		}
+	}
+	return false
+}
+
+func appendStepIssues(method TestMethod, issues *[]LintIssue) {
+	for _, issue := range ValidateSteps(method.steps) {
+		*issues = append(*issues, LintIssue{method.file, method.line, method.name + ": " + issue})
+	}
+	for _, subtest := range method.subtests {
+		appendStepIssues(subtest, issues)
+	}
+}
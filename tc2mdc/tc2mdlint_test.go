@@ -0,0 +1,85 @@
+package tc2mdc
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLint(t *testing.T) {
+	// > Lint
+	// # Lint() returns issues of tests and markers ordered by line
+	// ## GIVEN Input is
+	var input = strings.Join([]string{
+		"package somePackage",
+		"",
+		"import \"testing\"",
+		"",
+		// - "func TestBare(t *testing.T)" without markers
+		"func TestBare(t *testing.T) {",
+		"}",
+		"",
+		// - "func TestMarkers(t *testing.T)" with a scenario and malformed markers
+		"// # Scenario",
+		"func TestMarkers(t *testing.T) {",
+		"	//## WHEN act",
+		"	// ### THEN check",
+		"	// ##THEN check",
+		"	//  - extra space",
+		"	// -",
+		"	// -1 is not a marker",
+		"	// ---------------",
+		"	/* ## WHEN act",
+		"	   #### THEN check */",
+		"	// --verbose enables logs",
+		"	// #nosec G101",
+		"}",
+		"",
+		// - "func TestOrder(t *testing.T)" with steps THEN, WHEN, GIVEN
+		"// # Scenario",
+		"func TestOrder(t *testing.T) {",
+		"	// ## THEN check",
+		"	// ## WHEN act",
+		"	// ## GIVEN set",
+		"}",
+	}, "\n")
+
+	// ## WHEN Lint()
+	issues, err := Lint("some_test.go", []byte(input))
+	// ## THEN issues are:
+	require.Nil(t, err, "must be no error")
+	var lines []string
+	for _, issue := range issues {
+		lines = append(lines, issue.String())
+	}
+	require.Equal(t, []string{
+		// - 'TestBare' without scenario and steps
+		"some_test.go:5: TestBare: test without scenario",
+		"some_test.go:5: TestBare: test without GIVEN/WHEN/THEN steps",
+		// - malformed markers of 'TestMarkers', not markers like '--verbose' and '#nosec' are skipped
+		`some_test.go:10: no space between "//" and marker "##"`,
+		`some_test.go:11: unknown marker "###"`,
+		`some_test.go:12: no space after marker "##"`,
+		`some_test.go:13: extra space before marker "-"`,
+		`some_test.go:14: marker "-" without text`,
+		`some_test.go:18: unknown marker "####"`,
+		// - steps out of order of 'TestOrder'
+		"some_test.go:24: TestOrder: THEN step without WHEN step: check",
+		"some_test.go:24: TestOrder: GIVEN step after WHEN step: set",
+	}, lines)
+	// - accessors return parts of the issue
+	require.Equal(t, "some_test.go", issues[0].File())
+	require.Equal(t, 5, issues[0].Line())
+	require.Equal(t, "TestBare: test without scenario", issues[0].Message())
+}
+
+func TestLintSyntaxError(t *testing.T) {
+	// > Lint
+	// # Lint() returns an error of a file with a syntax error
+	// ## WHEN Lint() of "package somePackage func"
+	issues, err := Lint("some_test.go", []byte("package somePackage func"))
+	// ## THEN there is an error and no issues
+	require.NotNil(t, err, "must be an error")
+	require.Nil(t, issues)
+}
@@ -0,0 +1,50 @@
+package tc2mdc
+
+// MergePackages groups test data by package name and merges methods, suites and backgrounds of all files
+// of the same package into one test data. Packages, methods and suites keep the order of their first appearance.
+func MergePackages(testData []*TestData) []*TestData {
+	var packages []*TestData
+	byName := make(map[string]*TestData)
+	for _, fileData := range testData {
+		if fileData == nil {
+			continue
+		}
+		packageData, ok := byName[fileData.packageName]
+		if !ok {
+			packageData = &TestData{packageName: fileData.packageName}
+			byName[fileData.packageName] = packageData
+			packages = append(packages, packageData)
+		}
+		if packageData.title == "" {
+			packageData.title = fileData.title
+		}
+		mergeTOC(packageData, fileData.toc)
+		packageData.methods = append(packageData.methods, fileData.methods...)
+		packageData.background = append(packageData.background, fileData.background...)
+		for _, suite := range fileData.suites {
+			packageSuite := packageData.getSuite(suite.name)
+			packageSuite.background = append(packageSuite.background, suite.background...)
+		}
+		for suite, runners := range fileData.suiteRunners {
+			for _, runner := range runners {
+				packageData.addSuiteRunner(suite, runner)
+			}
+		}
+	}
+	return packages
+}
+
+// mergeTOC adds TOC lines of a file after the package methods merged so far.
+func mergeTOC(packageData *TestData, toc map[string]TOCLine) {
+	if len(toc) == 0 {
+		return
+	}
+	if packageData.toc == nil {
+		packageData.toc = make(map[string]TOCLine)
+	}
+	offset := len(packageData.methods)
+	for name, line := range toc {
+		line.index += offset
+		packageData.toc[name] = line
+	}
+}
@@ -0,0 +1,67 @@
+package tc2mdc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergePackagesEmpty(t *testing.T) {
+	// > Packages
+	// # MergePackages() returns 'nil' on 'nil' input
+	// ## WHEN MergePackages(nil)
+	packages := MergePackages(nil)
+	// ## THEN packages are 'nil'
+	require.Nil(t, packages, "packages must be nil")
+}
+
+func TestMergePackagesByName(t *testing.T) {
+	// > Packages
+	// # MergePackages() merges methods of files with the same package name in order of appearance
+	// ## GIVEN 3 files:
+	var files = []*TestData{
+		// - "packageName" = 'pkg1', "methods" = 'TestA'
+		{packageName: "pkg1", methods: []TestMethod{{name: "TestA"}}},
+		// - "packageName" = 'pkg2', "methods" = 'TestB'
+		{packageName: "pkg2", methods: []TestMethod{{name: "TestB"}}},
+		// - "packageName" = 'pkg1', "title" = 'Title', "methods" = 'TestC', 'TestD'
+		{packageName: "pkg1", title: "Title", methods: []TestMethod{{name: "TestC"}, {name: "TestD"}}},
+	}
+
+	// ## WHEN MergePackages()
+	packages := MergePackages(files)
+
+	// ## THEN 2 packages:
+	require.Equal(t, []*TestData{
+		// - "packageName" = 'pkg1', "title" = 'Title', "methods" = 'TestA', 'TestC', 'TestD'
+		{packageName: "pkg1", title: "Title", methods: []TestMethod{{name: "TestA"}, {name: "TestC"}, {name: "TestD"}}},
+		// - "packageName" = 'pkg2', "methods" = 'TestB'
+		{packageName: "pkg2", methods: []TestMethod{{name: "TestB"}}},
+	}, packages)
+}
+
+func TestMergePackagesTOC(t *testing.T) {
+	// > Packages, TOC
+	// # MergePackages() merges TOC lines of the same package with indexes following the merged methods
+	// ## GIVEN 2 files of package 'pkg':
+	var files = []*TestData{
+		// - "methods" = 'TestA', "TOC" = {0, 'A', '#testa'}
+		{packageName: "pkg", methods: []TestMethod{{name: "TestA"}},
+			toc: map[string]TOCLine{"TestA": {index: 0, caption: "A", link: "#testa"}}},
+		// - "methods" = 'TestB', "TOC" = {0, 'B', '#testb'}
+		{packageName: "pkg", methods: []TestMethod{{name: "TestB"}},
+			toc: map[string]TOCLine{"TestB": {index: 0, caption: "B", link: "#testb"}}},
+	}
+
+	// ## WHEN MergePackages()
+	packages := MergePackages(files)
+
+	// ## THEN 1 package with "TOC":
+	require.Equal(t, 1, len(packages))
+	require.Equal(t, map[string]TOCLine{
+		// - {0, 'A', '#testa'}
+		"TestA": {index: 0, caption: "A", link: "#testa"},
+		// - {1, 'B', '#testb'}
+		"TestB": {index: 1, caption: "B", link: "#testb"},
+	}, packages[0].toc)
+}
@@ -1,33 +1,110 @@
 package tc2mdc
 
 import (
-	"errors"
 	"fmt"
 	"regexp"
 	"strings"
+	"unicode"
 )
 
 // One line comment
 const OLC string = "//"
+
+// Block comment start and end
+const (
+	BCStart string = "/*"
+	BCEnd   string = "*/"
+)
+
+// Title marker of a file-level comment, e.g. "// #! Title"
+const TitleMarker string = "#!"
+
+// StepKind is the kind of a step by its marker.
+type StepKind int
+
 const (
-	GWT       = 0
-	common    = 1
-	indented  = 2
-	indented2 = 3
+	GWT       StepKind = 0 // "##" header step without a keyword
+	Common    StepKind = 1 // "-" bullet step
+	Indented  StepKind = 2 // "--" bullet step under a common one
+	Indented2 StepKind = 3 // "---" bullet step under an indented one
+	Given     StepKind = 4 // "## GIVEN" header step
+	When      StepKind = 5 // "## WHEN" header step
+	Then      StepKind = 6 // "## THEN" header step
+	And       StepKind = 7 // "## AND" header step
+	But       StepKind = 8 // "## BUT" header step
 )
 
+// String returns the lower case name of the kind, e.g. "gwt", "" for an unknown kind.
+func (kind StepKind) String() string {
+	switch kind {
+	case GWT:
+		{
+			return "gwt"
+		}
+	case Common:
+		{
+			return "common"
+		}
+	case Indented:
+		{
+			return "indented"
+		}
+	case Indented2:
+		{
+			return "indented2"
+		}
+	}
+	if kind.IsKeyword() {
+		return strings.ToLower(kind.Keyword())
+	}
+	return ""
+}
+
+// FuncKind is the kind of a test func by its name and signature as the go tool finds it.
+type FuncKind int
+
+const (
+	TestFunc      FuncKind = 0 // "func TestXxx(t *testing.T)"
+	BenchmarkFunc FuncKind = 1 // "func BenchmarkXxx(b *testing.B)"
+	FuzzFunc      FuncKind = 2 // "func FuzzXxx(f *testing.F)"
+	ExampleFunc   FuncKind = 3 // "func ExampleXxx()"
+)
+
+// Name prefixes of test funcs by kind
+var funcPrefixes = map[FuncKind]string{TestFunc: "Test", BenchmarkFunc: "Benchmark", FuzzFunc: "Fuzz", ExampleFunc: "Example"}
+
+// String returns the lower case name of the kind, e.g. "benchmark", "" for an unknown kind.
+func (kind FuncKind) String() string {
+	if prefix, ok := funcPrefixes[kind]; ok {
+		return strings.ToLower(prefix)
+	}
+	return ""
+}
+
+// TestStep is a step of a test scenario.
 type TestStep struct {
-	kind    int
+	kind    StepKind
 	comment string
 }
 
+// TestMethod is a test func with its scenario parsed from marker comments.
 type TestMethod struct {
-	name     string
-	tags     []string
-	scenario string
-	steps    []TestStep
+	name        string
+	kind        FuncKind
+	suite       string // testify suite of a "func (s *Suite) TestXxx()" method named as "Suite.TestXxx"
+	tags        []string
+	scenario    string
+	steps       []TestStep
+	description []string     // lines of the doc comment above the func
+	cases       []TestCase   // rows of a table-driven test
+	subtests    []TestMethod // "t.Run()" subtests named as "Parent/Child"
+	output      []string     // lines of the "// Output:" comment of an example
+	result      TestResult   // result of "go test -json" if applied
+	file        string       // source file path
+	line        int          // source line of the func
 }
 
+// TOCLine is a line of the table of contents linking to a test method.
 type TOCLine struct {
 	index   int
 	caption string
@@ -35,26 +112,189 @@ type TOCLine struct {
 	gitLink string
 }
 
+// TestData is the parse result of a test file or merged test files of a package.
 type TestData struct {
-	title       string
-	packageName string
-	toc         map[string]TOCLine
-	methods     []TestMethod
+	title        string
+	packageName  string
+	toc          map[string]TOCLine
+	methods      []TestMethod
+	suites       []TestSuite
+	suiteRunners map[string][]string // test funcs calling "suite.Run()" by suite name
+	background   []TestStep          // steps of "TestMain" and "// @background" funcs shared by all tests
+}
+
+// Kind returns the kind of the step.
+func (step TestStep) Kind() StepKind {
+	return step.kind
+}
+
+// Comment returns the text of the step without the marker and the keyword.
+func (step TestStep) Comment() string {
+	return step.comment
+}
+
+// Name returns the func name, "Parent/Child" for a subtest.
+func (method TestMethod) Name() string {
+	return method.name
+}
+
+// Kind returns the kind of the func: a test, a benchmark, a fuzz test or an example.
+func (method TestMethod) Kind() FuncKind {
+	return method.kind
+}
+
+// Output returns the expected output of an example, 'nil' if there is no "// Output:" comment.
+func (method TestMethod) Output() []string {
+	return append([]string(nil), method.output...)
+}
+
+// Tags returns the tags of the ">" marker.
+func (method TestMethod) Tags() []string {
+	return append([]string(nil), method.tags...)
+}
+
+// Scenario returns the scenario of the "#" marker.
+func (method TestMethod) Scenario() string {
+	return method.scenario
+}
+
+// Steps returns the steps in the order of the source.
+func (method TestMethod) Steps() []TestStep {
+	return append([]TestStep(nil), method.steps...)
+}
+
+// Description returns the lines of the doc comment above the func.
+func (method TestMethod) Description() []string {
+	return append([]string(nil), method.description...)
+}
+
+// Cases returns the rows of a table-driven test.
+func (method TestMethod) Cases() []TestCase {
+	return append([]TestCase(nil), method.cases...)
+}
+
+// Subtests returns the "t.Run()" subtests.
+func (method TestMethod) Subtests() []TestMethod {
+	return append([]TestMethod(nil), method.subtests...)
+}
+
+// Result returns the result of "go test -json", its action is empty if no results are applied.
+func (method TestMethod) Result() TestResult {
+	return method.result
+}
+
+// File returns the source file path, empty if it is unknown.
+func (method TestMethod) File() string {
+	return method.file
+}
+
+// Line returns the source line of the func, 0 if it is unknown.
+func (method TestMethod) Line() int {
+	return method.line
+}
+
+// Index returns the 0-based position of the line in the TOC.
+func (line TOCLine) Index() int {
+	return line.index
+}
+
+// Caption returns the scenario of the method or its name as code.
+func (line TOCLine) Caption() string {
+	return line.caption
+}
+
+// Link returns the anchor link of the method header, e.g. "#testname".
+func (line TOCLine) Link() string {
+	return line.link
+}
+
+// GitLink returns the link to the source of the method, empty if it is not set.
+func (line TOCLine) GitLink() string {
+	return line.gitLink
+}
+
+// Title returns the title of the "#!" marker.
+func (data *TestData) Title() string {
+	return data.title
+}
+
+// PackageName returns the name of the parsed package.
+func (data *TestData) PackageName() string {
+	return data.packageName
 }
 
+// Methods returns the test methods in the order of the source.
+func (data *TestData) Methods() []TestMethod {
+	return append([]TestMethod(nil), data.methods...)
+}
+
+// Background returns the steps shared by all tests, 'nil' if there are no background markers.
+func (data *TestData) Background() []TestStep {
+	return append([]TestStep(nil), data.background...)
+}
+
+// TOC returns the TOC lines ordered by index.
+func (data *TestData) TOC() []TOCLine {
+	return getSortedTOC(data.toc)
+}
+
+var (
+	rePackage = regexp.MustCompile(`^package\s(?P<name>\w+)`)
+	reFunc    = regexp.MustCompile(`^func\s(?P<name>(?:Test|Benchmark|Fuzz)\w*)\(\w+ \*testing\.[TBF]\)|^func\s(?P<example>Example\w*)\(\)`)
+	reMethod  = regexp.MustCompile(`^func\s\(\w+ \*?(?P<suite>\w+)\)\s(?P<name>Test\w*)\(\)`)
+)
+
+// Parse parses lines of Go test code. Complete Go files are parsed with go/parser,
+// other code (e.g. a snippet without "package") falls back to the line-based parsing.
+// The error is ParseErrors: malformed markers of a complete file are returned with the data,
+// syntax errors of a file with "package" are returned with the data of the line-based parsing.
 func Parse(codeLines []string) (*TestData, error) {
 	errorMessage := isInputEmpty(&codeLines)
 	if errorMessage != "" {
-		return nil, errors.New(errorMessage)
+		return nil, ParseErrors{{Reason: errorMessage}}
 	}
 	fmt.Println("Start parsing...")
 
+	testData, err := parseGoFile("", []byte(strings.Join(codeLines, "\n")), defaultGrammar)
+	if testData == nil {
+		testData = parseLines(codeLines)
+		if testData.packageName == "" {
+			err = nil // a snippet, not a file with syntax errors
+		}
+	}
+	fillTOC(testData)
+	fmt.Printf("Parsed package %v with %d methods.", testData.packageName, len(testData.methods))
+	return testData, err
+}
+
+// ParseFile parses the source of a test file, "filename" is used for positions and the language:
+// Java, Kotlin, Python, TypeScript and Rust files are detected by the extension (see Languages()), others are Go.
+// Unlike Parse() it prints nothing, so reports of the caller can use stdout.
+// The error is ParseErrors: syntax errors are returned without data, malformed markers with it.
+// Markers of other grammars are parsed by Grammar.ParseFile().
+func ParseFile(filename string, src []byte) (*TestData, error) {
+	return parseFile(filename, src, defaultGrammar)
+}
+
+func parseFile(filename string, src []byte, grammar *markerGrammar) (*TestData, error) {
+	if len(src) == 0 {
+		return nil, ParseErrors{{File: filename, Reason: "empty input"}}
+	}
+
+	testData, err := parseSource(filename, src, grammar)
+	if testData == nil {
+		return nil, err
+	}
+	fillTOC(testData)
+	return testData, err
+}
+
+// parseLines is the line-based parsing: a test func starts with a "func TestXxx(t *testing.T)" line
+// and ends with a not indented "}" line.
+func parseLines(codeLines []string) *TestData {
 	var testData = new(TestData)
 	var isFuncStarted bool
 
-	rePackage, _ := regexp.Compile(`^package\s(?P<name>\w+)`)
-	reFunc, _ := regexp.Compile(`^func\s(?P<name>Test\w+)\(t \*testing\.T\)`)
-	reMarker, _ := regexp.Compile(`^\s(#|##|>|-|--|---)\s[^\s]`) // all MD markers to search for
 	for _, origLine := range codeLines {
 		trimmedLine := strings.TrimSpace(origLine)
 		switch {
@@ -64,12 +304,14 @@ func Parse(codeLines []string) (*TestData, error) {
 			}
 		case strings.HasPrefix(origLine, "func"): // start of func
 			{
-				isFuncStarted = parseFunc(origLine, reFunc, testData)
+				isFuncStarted = parseFunc(origLine, reFunc, testData) || parseSuiteMethod(origLine, reMethod, testData)
 			}
 		case strings.HasPrefix(trimmedLine, OLC):
 			{
 				if isFuncStarted {
-					parseOneLineComment(trimmedLine, reMarker, &(testData.methods[len(testData.methods)-1]))
+					parseOneLineComment(trimmedLine, defaultGrammar, &(testData.methods[len(testData.methods)-1]))
+				} else {
+					parseTitle(trimmedLine, defaultGrammar, testData)
 				}
 			}
 		case strings.HasPrefix(origLine, "}"): // end of func
@@ -78,33 +320,97 @@ func Parse(codeLines []string) (*TestData, error) {
 			}
 		}
 	}
-	fmt.Printf("Parsed package %v with %d methods.", testData.packageName, len(testData.methods))
-	return testData, nil
+	return testData
 }
 
-func parseOneLineComment(line string, re *regexp.Regexp, testMethod *TestMethod) {
-	line = line[len(OLC):] // trim OLC
-	if re.MatchString(line) {
-		marker := re.FindStringSubmatch(line)
-		line = line[1:] // trim a leading space
-		switch marker[1] {
-		case "#":
+// fillTOC adds a TOC line per test method: its scenario (or name) linked to the method header.
+func fillTOC(testData *TestData) {
+	if len(testData.methods) == 0 {
+		return
+	}
+	testData.toc = make(map[string]TOCLine)
+	for i, method := range testData.methods {
+		caption := method.scenario
+		if caption == "" {
+			caption = "`" + method.name + "`"
+		}
+		testData.toc[method.name] = TOCLine{index: i, caption: caption, link: "#" + getAnchor("`"+method.name+"`")}
+	}
+}
+
+// getAnchor returns the anchor of a MD header the way GitHub generates it:
+// lower case, punctuation removed, spaces replaced with '-'.
+func getAnchor(header string) string {
+	var anchor strings.Builder
+	for _, r := range strings.ToLower(header) {
+		switch {
+		case unicode.IsLetter(r), unicode.IsDigit(r), r == '-', r == '_':
+			anchor.WriteRune(r)
+		case r == ' ':
+			anchor.WriteRune('-')
+		}
+	}
+	return anchor.String()
+}
+
+// parseTitle sets the title from a file-level "// #! Title" comment, the first one wins.
+func parseTitle(line string, grammar *markerGrammar, testData *TestData) {
+	if testData.title != "" || !strings.HasPrefix(line, grammar.prefix) {
+		return
+	}
+	result := getMatchesMap(grammar.title, line[len(grammar.prefix):])
+	testData.title = strings.TrimSpace(result["title"])
+}
+
+// parseBlockComment parses each line of a block comment as a one line comment,
+// the comment starts with "/*" and the rest of the prefix of the grammar, e.g. "/*:" for "//:".
+func parseBlockComment(text string, grammar *markerGrammar, testMethod *TestMethod) {
+	if !strings.HasPrefix(text, grammar.blockStart) {
+		return
+	}
+	for _, line := range getBlockLines(BCStart + text[len(grammar.blockStart):]) {
+		if line != "" {
+			parseOneLineComment(grammar.prefix+" "+line, grammar, testMethod)
+		}
+	}
+}
+
+// getBlockLines returns trimmed lines of a block comment, a leading '*' of godoc-style lines is skipped.
+func getBlockLines(text string) []string {
+	text = strings.TrimSuffix(strings.TrimPrefix(text, BCStart), BCEnd)
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		lines = append(lines, strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "*")))
+	}
+	return lines
+}
+
+func parseOneLineComment(line string, grammar *markerGrammar, testMethod *TestMethod) {
+	if !strings.HasPrefix(line, grammar.prefix) {
+		return
+	}
+	line = line[len(grammar.prefix):] // trim the prefix
+	if grammar.marker.MatchString(line) {
+		marker := grammar.marker.FindStringSubmatch(line)[1]
+		line = line[1+len(marker)+1:] // trim a leading space, the marker and a space after it
+		switch grammar.roles[marker] {
+		case roleScenario:
 			{
-				testMethod.scenario = line[2:]
+				testMethod.scenario = line
 			}
-		case ">":
+		case roleTags:
 			{
-				for _, tag := range strings.Split(line[2:], ",") {
+				for _, tag := range strings.Split(line, ",") {
 					testMethod.tags = append(testMethod.tags, strings.TrimSpace(tag))
 				}
 			}
-		case "##":
+		case roleStep:
 			{
-				testMethod.steps = append(testMethod.steps, TestStep{GWT, strings.TrimSpace(line[3:])})
+				testMethod.steps = append(testMethod.steps, getGWTStep(strings.TrimSpace(line)))
 			}
-		case "-", "--", "---":
+		case roleBullet:
 			{
-				testMethod.steps = append(testMethod.steps, TestStep{len(marker[1]), strings.TrimSpace(line[len(marker[1]):])})
+				testMethod.steps = append(testMethod.steps, TestStep{grammar.bullets[marker], strings.TrimSpace(line)})
 			}
 		}
 	}
@@ -112,15 +418,38 @@ func parseOneLineComment(line string, re *regexp.Regexp, testMethod *TestMethod)
 
 func parseFunc(origLine string, reFunc *regexp.Regexp, testData *TestData) bool {
 	result := getMatchesMap(reFunc, origLine)
-	funcName := result["name"]
+	funcName := result["name"] + result["example"]
 	if funcName != "" {
-		method := TestMethod{name: funcName}
+		method := TestMethod{name: funcName, kind: getFuncKind(funcName)}
 		(*testData).methods = append((*testData).methods, method)
 		return true
 	}
 	return false
 }
 
+// parseSuiteMethod adds a test method of a testify suite, markers of its setup methods are parsed
+// from complete files only.
+func parseSuiteMethod(origLine string, reMethod *regexp.Regexp, testData *TestData) bool {
+	result := getMatchesMap(reMethod, origLine)
+	if result == nil {
+		return false
+	}
+	testData.getSuite(result["suite"])
+	method := TestMethod{name: result["suite"] + "." + result["name"], suite: result["suite"]}
+	testData.methods = append(testData.methods, method)
+	return true
+}
+
+// getFuncKind returns the kind of a test func by its name prefix.
+func getFuncKind(name string) FuncKind {
+	for _, kind := range []FuncKind{BenchmarkFunc, FuzzFunc, ExampleFunc} {
+		if strings.HasPrefix(name, funcPrefixes[kind]) {
+			return kind
+		}
+	}
+	return TestFunc
+}
+
 func parsePackageHeader(origLine string, rePackage *regexp.Regexp, testData *TestData) {
 	result := getMatchesMap(rePackage, origLine)
 	packageName := result["name"]
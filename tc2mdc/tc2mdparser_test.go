@@ -1,6 +1,7 @@
 package tc2mdc
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -60,6 +61,49 @@ func TestGoPackageName(t *testing.T) {
 	require.Nil(t, testData.methods, "methodsm must be nil")
 }
 
+func TestGoTitle(t *testing.T) {
+	// > Title, Go
+	// # Parse() returns data with "title" from the first file-level title comment
+	// ## GIVEN Input is
+	var input = []string{
+		// - "// #! Some Title" // above the package
+		OLC + " " + TitleMarker + " Some Title",
+		// - "package somePackage"
+		"package somePackage",
+		// - "// #! Other Title" // ignored as the second one
+		OLC + " " + TitleMarker + " Other Title",
+		// - "func TestSomething(t *testing.T) {"
+		"func TestSomething(t *testing.T) {",
+		// - "// #! Func Title" // ignored inside of a func
+		OLC + " " + TitleMarker + " Func Title",
+		"}",
+	}
+
+	// ## WHEN Parse()
+	testData, err := Parse(input)
+	// ## THEN output data has "title" = 'Some Title'
+	require.Nil(t, err, "must be no error")
+	require.Equal(t, "Some Title", testData.title)
+}
+
+func TestGoTitleLines(t *testing.T) {
+	// > Title, Go
+	// # Parse() returns data with "title" from a title comment of a snippet which is not a Go file
+	// ## GIVEN Input is
+	var input = []string{
+		// - "// #! Some Title"
+		OLC + " " + TitleMarker + " Some Title",
+		// - " something"
+		" something",
+	}
+
+	// ## WHEN Parse()
+	testData, err := Parse(input)
+	// ## THEN output data has "title" = 'Some Title'
+	require.Nil(t, err, "must be no error")
+	require.Equal(t, "Some Title", testData.title)
+}
+
 func TestGoFuncNameAsMethodName(t *testing.T) {
 	// > Methods, Go
 	// # Parse() returns data with 1 element in "Methods" on input with 1 test func and 1 non-test func.
@@ -85,10 +129,13 @@ func TestGoFuncNameAsMethodName(t *testing.T) {
 	testData, err := Parse(input)
 	// ## THEN output data has:
 	require.Nil(t, err, "must be no error")
-	// - "packageName" = 'somePackage', ("title", "TOC") = '<empty>'
+	// - "packageName" = 'somePackage', "title" = '<empty>'
 	require.Equal(t, "somePackage", testData.packageName)
 	require.Empty(t, testData.title, "title must be empty")
-	require.Nil(t, testData.toc, "TOC must be empty")
+	// - "TOC" contains 1 line: "index" = 0, "caption" = '`TestSomething`', "link" = '#testsomething'
+	require.Equal(t, map[string]TOCLine{
+		"TestSomething": {index: 0, caption: "`TestSomething`", link: "#testsomething"},
+	}, testData.toc)
 	// - "Methods" contains 1 element: "name" = 'TestSomething', other fields are empty
 	require.Equal(t, 1, len(testData.methods))
 	require.Equal(t, "TestSomething", testData.methods[0].name)
@@ -116,10 +163,13 @@ func TestGoFuncNameScenario(t *testing.T) {
 	testData, err := Parse(input)
 	// ## THEN output is:
 	require.Nil(t, err, "must be no error")
-	// - "packageName" = 'somePackage', ("title", "TOC") = '<empty>'
+	// - "packageName" = 'somePackage', "title" = '<empty>'
 	require.Equal(t, "somePackage", testData.packageName)
 	require.Empty(t, testData.title, "title must be empty")
-	require.Empty(t, testData.toc, "TOC must be empty")
+	// - "TOC" contains 1 line with the scenario as "caption": {0, 'Scenario', '#testsomething'}
+	require.Equal(t, map[string]TOCLine{
+		"TestSomething": {index: 0, caption: "Scenario", link: "#testsomething"},
+	}, testData.toc)
 	// - "Methods" contains 1 element: "name" = 'TestSomething', "scenario" = 'Scenario', other fields are empty
 	require.Equal(t, 1, len(testData.methods))
 	require.Equal(t, "TestSomething", testData.methods[0].name)
@@ -147,10 +197,13 @@ func TestGoFuncNameTags(t *testing.T) {
 	testData, err := Parse(input)
 	// ## THEN output is:
 	require.Nil(t, err, "must be no error")
-	// - "packageName" = 'somePackage', ("title", "TOC") = '<empty>'
+	// - "packageName" = 'somePackage', "title" = '<empty>'
 	require.Equal(t, "somePackage", testData.packageName)
 	require.Empty(t, testData.title, "title must be empty")
-	require.Empty(t, testData.toc, "TOC must be empty")
+	// - "TOC" contains 1 line: {0, '`TestSomething`', '#testsomething'}
+	require.Equal(t, map[string]TOCLine{
+		"TestSomething": {index: 0, caption: "`TestSomething`", link: "#testsomething"},
+	}, testData.toc)
 	// - "Methods" contains 1 element:
 	require.Equal(t, 1, len(testData.methods))
 	// -- "name" = 'TestSomething', "scenario" = '<empty>', other fields are empty
@@ -165,7 +218,7 @@ func TestGoFuncNameTags(t *testing.T) {
 
 func TestGoFuncNameGWT(t *testing.T) {
 	// > Comments, Go
-	// # Parse() returns data with an element in "Methods" with 3 steps - 'GWT' comments
+	// # Parse() returns data with an element in "Methods" with 3 steps - 'GWT' comments classified by keywords
 	// ## GIVEN Input is
 	var input = []string{
 		// - "func TestSomething(t *testing.T) {"
@@ -188,15 +241,15 @@ func TestGoFuncNameGWT(t *testing.T) {
 	require.Equal(t, 1, len(testData.methods))
 	// -- 3 "steps" :
 	require.Equal(t, 3, len(testData.methods[0].steps))
-	// --- {0, 'GIVEN set'}
-	require.Equal(t, GWT, testData.methods[0].steps[0].kind)
-	require.Equal(t, "GIVEN set", testData.methods[0].steps[0].comment)
-	// --- {0, 'WHEN act'}
-	require.Equal(t, GWT, testData.methods[0].steps[1].kind)
-	require.Equal(t, "WHEN act", testData.methods[0].steps[1].comment)
-	// --- {0, 'THEN check'}
-	require.Equal(t, GWT, testData.methods[0].steps[2].kind)
-	require.Equal(t, "THEN check", testData.methods[0].steps[2].comment)
+	// --- {Given, 'set'}
+	require.Equal(t, Given, testData.methods[0].steps[0].kind)
+	require.Equal(t, "set", testData.methods[0].steps[0].comment)
+	// --- {When, 'act'}
+	require.Equal(t, When, testData.methods[0].steps[1].kind)
+	require.Equal(t, "act", testData.methods[0].steps[1].comment)
+	// --- {Then, 'check'}
+	require.Equal(t, Then, testData.methods[0].steps[2].kind)
+	require.Equal(t, "check", testData.methods[0].steps[2].comment)
 }
 
 func TestGoFuncNameSteps123(t *testing.T) {
@@ -225,12 +278,102 @@ func TestGoFuncNameSteps123(t *testing.T) {
 	// -- 3 "steps" :
 	require.Equal(t, 3, len(testData.methods[0].steps))
 	// --- {1, 'common comment'}
-	require.Equal(t, common, testData.methods[0].steps[0].kind)
+	require.Equal(t, Common, testData.methods[0].steps[0].kind)
 	require.Equal(t, "common comment", testData.methods[0].steps[0].comment)
 	// --- {2, 'indented comment'}
-	require.Equal(t, indented, testData.methods[0].steps[1].kind)
+	require.Equal(t, Indented, testData.methods[0].steps[1].kind)
 	require.Equal(t, "indented comment", testData.methods[0].steps[1].comment)
 	// --- {3, 'indented2 comment'}
-	require.Equal(t, indented2, testData.methods[0].steps[2].kind)
+	require.Equal(t, Indented2, testData.methods[0].steps[2].kind)
 	require.Equal(t, "indented twice comment", testData.methods[0].steps[2].comment)
 }
+
+func TestGoTOCLines(t *testing.T) {
+	// > TOC, Go
+	// # Parse() returns data with a "TOC" line per test func in order of funcs
+	// ## GIVEN Input is
+	var input = []string{
+		"package somePackage",
+		// - "func TestFirst(t *testing.T)" with "// # First scenario"
+		"func TestFirst(t *testing.T) {",
+		OLC + " # First scenario",
+		"}",
+		// - "func TestSecond_Case(t *testing.T)" without scenario
+		"func TestSecond_Case(t *testing.T) {",
+		"}",
+	}
+
+	// ## WHEN Parse()
+	testData, err := Parse(input)
+	// ## THEN output data has "TOC" with 2 lines:
+	require.Nil(t, err, "must be no error")
+	require.Equal(t, map[string]TOCLine{
+		// - {0, 'First scenario', '#testfirst'}
+		"TestFirst": {index: 0, caption: "First scenario", link: "#testfirst"},
+		// - {1, '`TestSecond_Case`', '#testsecond_case'}
+		"TestSecond_Case": {index: 1, caption: "`TestSecond_Case`", link: "#testsecond_case"},
+	}, testData.toc)
+}
+
+func TestPublicAPI(t *testing.T) {
+	// > Public API
+	// # Accessors return parsed data, modifying the returned slices does not change it
+	// ## GIVEN Input is
+	var input = []byte(strings.Join([]string{
+		// - "// #! Title" above "package somePackage"
+		"// #! Title",
+		"package somePackage",
+		"",
+		"import \"testing\"",
+		"",
+		// - "func TestFirst(t *testing.T)" with tags 'T1', scenario 'First', 'GWT' step 'WHEN act' and common step 'Step'
+		"func TestFirst(t *testing.T) {",
+		OLC + " > T1",
+		OLC + " # First",
+		OLC + " ## WHEN act",
+		OLC + " - Step",
+		"}",
+	}, "\n"))
+
+	// ## WHEN ParseFile()
+	testData, err := ParseFile("some_test.go", input)
+	require.Nil(t, err, "must be no error")
+
+	// ## THEN data has "Title" = 'Title', "PackageName" = 'somePackage' and 1 method:
+	require.Equal(t, "Title", testData.Title())
+	require.Equal(t, "somePackage", testData.PackageName())
+	methods := testData.Methods()
+	require.Equal(t, 1, len(methods))
+	// - "Name" = 'TestFirst', "File" = 'some_test.go', "Line" = 6, "Tags" = 'T1', "Scenario" = 'First'
+	method := methods[0]
+	require.Equal(t, "TestFirst", method.Name())
+	require.Equal(t, "some_test.go", method.File())
+	require.Equal(t, 6, method.Line())
+	require.Equal(t, []string{"T1"}, method.Tags())
+	require.Equal(t, "First", method.Scenario())
+	// - "Steps": {'when', 'act'}, {'common', 'Step'}
+	steps := method.Steps()
+	require.Equal(t, 2, len(steps))
+	require.Equal(t, When, steps[0].Kind())
+	require.Equal(t, "when", steps[0].Kind().String())
+	require.Equal(t, "act", steps[0].Comment())
+	require.Equal(t, "common", steps[1].Kind().String())
+	// - no result
+	require.Equal(t, "", method.Result().Action())
+	// - "TOC" has 1 line {0, 'First', '#testfirst'}
+	toc := testData.TOC()
+	require.Equal(t, 1, len(toc))
+	require.Equal(t, 0, toc[0].Index())
+	require.Equal(t, "First", toc[0].Caption())
+	require.Equal(t, "#testfirst", toc[0].Link())
+	require.Equal(t, "", toc[0].GitLink())
+
+	// ## WHEN returned slices are modified
+	methods[0].name = "Changed"
+	method.Tags()[0] = "Changed"
+	// ## THEN data is the same
+	require.Equal(t, "TestFirst", testData.Methods()[0].Name())
+	require.Equal(t, []string{"T1"}, testData.Methods()[0].Tags())
+	// - the name of an unknown kind is empty
+	require.Equal(t, "", StepKind(9).String())
+}
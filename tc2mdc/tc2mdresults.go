@@ -0,0 +1,199 @@
+package tc2mdc
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Test actions of "go test -json" events which are final results of a test
+const (
+	ActionPass = "pass"
+	ActionFail = "fail"
+	ActionSkip = "skip"
+)
+
+// TestResult is the final result of a test.
+type TestResult struct {
+	action  string   // one of ActionPass, ActionFail, ActionSkip
+	elapsed float64  // seconds
+	output  []string // output lines of a failed test
+}
+
+// Action returns one of ActionPass, ActionFail, ActionSkip or empty if there is no result.
+func (result TestResult) Action() string {
+	return result.action
+}
+
+// Elapsed returns the duration of the test in seconds.
+func (result TestResult) Elapsed() float64 {
+	return result.elapsed
+}
+
+// Output returns the output lines of a failed test.
+func (result TestResult) Output() []string {
+	return append([]string(nil), result.output...)
+}
+
+// TestResults are results of tests by package import path and test name, e.g. "Parent/Child" of a subtest.
+type TestResults map[string]map[string]TestResult
+
+// testEvent is an event of "go test -json" output.
+type testEvent struct {
+	Action  string
+	Package string
+	Test    string
+	Elapsed float64
+	Output  string
+}
+
+// ParseTestResults reads "go test -json" events and returns the final results of tests with output of
+// failed ones, not JSON lines (e.g. build errors) are skipped.
+func ParseTestResults(reader io.Reader) (TestResults, error) {
+	results := make(TestResults)
+	outputs := make(map[string][]string) // by package and test
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, "{") {
+			continue
+		}
+		var event testEvent
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			return nil, err
+		}
+		if event.Test == "" {
+			continue
+		}
+		key := event.Package + " " + event.Test
+		switch event.Action {
+		case "output":
+			{
+				if isFailureOutput(event.Output) {
+					outputs[key] = append(outputs[key], strings.TrimRight(event.Output, "\n"))
+				}
+			}
+		case ActionPass, ActionFail, ActionSkip:
+			{
+				if results[event.Package] == nil {
+					results[event.Package] = make(map[string]TestResult)
+				}
+				result := TestResult{action: event.Action, elapsed: event.Elapsed}
+				if event.Action == ActionFail {
+					result.output = outputs[key]
+				}
+				delete(outputs, key)
+				results[event.Package][event.Test] = result
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// isFailureOutput checks the output line is not a framing one like "=== RUN" or "--- FAIL:".
+func isFailureOutput(output string) bool {
+	return !strings.HasPrefix(output, "=== ") && !strings.HasPrefix(output, "--- ")
+}
+
+// ApplyTestResults sets results to test methods and their subtests by name, results of the package
+// with the import path are used (see GetImportPath()). If the import path is empty, results of the only
+// package which import path ends with the package name (or its "_test" one) are used.
+// Results of a suite method "Suite.TestXxx" are of "TestRunner/TestXxx" where "TestRunner" calls
+// "suite.Run()" of the suite in the same test data (a file or merged files of the package).
+func ApplyTestResults(data *TestData, results TestResults, importPath string) {
+	if data == nil {
+		return
+	}
+	if importPath == "" {
+		importPath = getResultsPackage(results, strings.TrimSuffix(data.packageName, "_test"))
+	}
+	if tests, ok := results[importPath]; ok {
+		for i := range data.methods {
+			method := &data.methods[i]
+			if method.suite == "" {
+				applyTestResult(method, tests, "", "")
+				continue
+			}
+			for _, runner := range data.suiteRunners[method.suite] {
+				applyTestResult(method, tests, method.suite+".", runner+"/")
+			}
+		}
+	}
+}
+
+// getResultsPackage returns the import path of results which last element is the package name,
+// empty if there are no such paths or there are several ones.
+func getResultsPackage(results TestResults, packageName string) string {
+	var packagePaths []string
+	for packagePath := range results {
+		if path.Base(packagePath) == packageName {
+			packagePaths = append(packagePaths, packagePath)
+		}
+	}
+	if len(packagePaths) != 1 {
+		return ""
+	}
+	return packagePaths[0]
+}
+
+// GetImportPath returns the import path of the Go package in the directory by the module path
+// of the nearest "go.mod" in the directory or its parents.
+func GetImportPath(dir string) (string, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	for modDir := absDir; ; {
+		content, err := os.ReadFile(filepath.Join(modDir, "go.mod"))
+		if err == nil {
+			modulePath := getModulePath(string(content))
+			if modulePath == "" {
+				return "", errors.New(filepath.Join(modDir, "go.mod") + ": no module path")
+			}
+			relDir, err := filepath.Rel(modDir, absDir)
+			if err != nil {
+				return "", err
+			}
+			return path.Join(modulePath, filepath.ToSlash(relDir)), nil
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return "", err
+		}
+		parent := filepath.Dir(modDir)
+		if parent == modDir {
+			return "", errors.New("go.mod not found for " + dir)
+		}
+		modDir = parent
+	}
+}
+
+// getModulePath returns the path of the "module" directive of a go.mod file, empty if there is none.
+func getModulePath(goMod string) string {
+	for _, line := range strings.Split(goMod, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 2 && fields[0] == "module" {
+			return strings.Trim(fields[1], `"`)
+		}
+	}
+	return ""
+}
+
+// applyTestResult sets results to the method and its subtests by name, the name prefix of a suite method
+// is replaced with the prefix of its results, e.g. "Suite." with "TestSuite/".
+func applyTestResult(method *TestMethod, tests map[string]TestResult, namePrefix string, resultPrefix string) {
+	if result, ok := tests[resultPrefix+strings.TrimPrefix(method.name, namePrefix)]; ok {
+		method.result = result
+	}
+	for i := range method.subtests {
+		applyTestResult(&method.subtests[i], tests, namePrefix, resultPrefix)
+	}
+}
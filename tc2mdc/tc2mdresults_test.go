@@ -0,0 +1,177 @@
+package tc2mdc
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResultsParseEvents(t *testing.T) {
+	// > Test results
+	// # ParseTestResults() returns final results of tests by package and test name
+	// ## GIVEN "go test -json" output with
+	var input = strings.Join([]string{
+		// - a not JSON line
+		"# some/pkg [build failed]",
+		// - 'run', 'output' and 'pass' events of 'TestA'
+		`{"Action":"run","Package":"some/pkg","Test":"TestA"}`,
+		`{"Action":"output","Package":"some/pkg","Test":"TestA","Output":"=== RUN   TestA\n"}`,
+		`{"Action":"pass","Package":"some/pkg","Test":"TestA","Elapsed":0.5}`,
+		// - 'output' events of 'TestB/child': framing lines and an assertion message
+		`{"Action":"output","Package":"some/pkg","Test":"TestB/child","Output":"=== RUN   TestB/child\n"}`,
+		`{"Action":"output","Package":"some/pkg","Test":"TestB/child","Output":"    b_test.go:12: \n"}`,
+		`{"Action":"output","Package":"some/pkg","Test":"TestB/child","Output":"        \tError: Not equal\n"}`,
+		`{"Action":"output","Package":"some/pkg","Test":"TestB/child","Output":"--- FAIL: TestB/child (0.01s)\n"}`,
+		// - 'fail' event of 'TestB/child' and 'skip' event of 'TestC'
+		`{"Action":"fail","Package":"some/pkg","Test":"TestB/child","Elapsed":0.01}`,
+		`{"Action":"skip","Package":"other/pkg","Test":"TestC"}`,
+		// - 'pass' event of the package
+		`{"Action":"pass","Package":"some/pkg","Elapsed":1}`,
+	}, "\n")
+
+	// ## WHEN ParseTestResults()
+	results, err := ParseTestResults(strings.NewReader(input))
+
+	// ## THEN no error, results are:
+	require.Nil(t, err, "must be no error")
+	require.Equal(t, TestResults{
+		// - 'some/pkg': 'TestA' passed in 0.5s without output
+		// - 'TestB/child' failed in 0.01s with output lines except framing ones
+		"some/pkg": {
+			"TestA": {action: ActionPass, elapsed: 0.5},
+			"TestB/child": {action: ActionFail, elapsed: 0.01,
+				output: []string{"    b_test.go:12: ", "        \tError: Not equal"}},
+		},
+		// - 'other/pkg': 'TestC' skipped
+		"other/pkg": {"TestC": {action: ActionSkip}},
+	}, results)
+}
+
+func TestResultsParseError(t *testing.T) {
+	// > Test results
+	// # ParseTestResults() returns error on a broken JSON event
+	// ## WHEN ParseTestResults('{"Action":')
+	results, err := ParseTestResults(strings.NewReader(`{"Action":`))
+	// ## THEN error, results are 'nil'
+	require.Error(t, err)
+	require.Nil(t, results, "results must be nil")
+}
+
+func TestResultsApply(t *testing.T) {
+	// > Test results
+	// # ApplyTestResults() sets results of the package with the import path to methods and subtests
+	// ## GIVEN - testData of package 'main' with methods 'TestA', 'TestB' with subtest 'TestB/child'
+	var testData = &TestData{packageName: "main", methods: []TestMethod{
+		{name: "TestA"},
+		{name: "TestB", subtests: []TestMethod{{name: "TestB/child"}}},
+	}}
+	// - results of 'some/cmd' and 'other/main'
+	var results = TestResults{
+		"some/cmd":   {"TestA": {action: ActionPass, elapsed: 1}, "TestB/child": {action: ActionFail}},
+		"other/main": {"TestB": {action: ActionSkip}},
+	}
+
+	// ## WHEN ApplyTestResults() with the import path 'some/cmd'
+	ApplyTestResults(testData, results, "some/cmd")
+
+	// ## THEN results of 'some/cmd' are set:
+	// - 'TestA' passed
+	require.Equal(t, TestResult{action: ActionPass, elapsed: 1}, testData.methods[0].result)
+	// - 'TestB' has no result, 'TestB/child' failed
+	require.Equal(t, TestResult{}, testData.methods[1].result)
+	require.Equal(t, TestResult{action: ActionFail}, testData.methods[1].subtests[0].result)
+}
+
+func TestResultsApplyByPackageName(t *testing.T) {
+	// > Test results
+	// # ApplyTestResults() without the import path sets results of the only package with the same last path element
+	// ## GIVEN - testData of package 'util_test' with a method 'TestA'
+	newData := func() *TestData {
+		return &TestData{packageName: "util_test", methods: []TestMethod{{name: "TestA"}}}
+	}
+	// - results of 'a/util' and 'b/other'
+	var results = TestResults{
+		"a/util":  {"TestA": {action: ActionPass}},
+		"b/other": {"TestA": {action: ActionFail}},
+	}
+
+	// ## WHEN ApplyTestResults() with the empty import path
+	testData := newData()
+	ApplyTestResults(testData, results, "")
+	// ## THEN results of 'a/util' are set
+	require.Equal(t, TestResult{action: ActionPass}, testData.methods[0].result)
+
+	// ## WHEN ApplyTestResults() with results of 'b/util' too
+	results["b/util"] = map[string]TestResult{"TestA": {action: ActionSkip}}
+	testData = newData()
+	ApplyTestResults(testData, results, "")
+	// ## THEN no results are set as the package is ambiguous
+	require.Equal(t, TestResult{}, testData.methods[0].result)
+}
+
+func TestGetImportPath(t *testing.T) {
+	// > Test results
+	// # GetImportPath() returns the import path of a directory by the module path of the nearest go.mod
+	// ## GIVEN a module 'example.com/mod' with a directory 'pkg/sub'
+	root := t.TempDir()
+	require.Nil(t, os.MkdirAll(filepath.Join(root, "mod", "pkg", "sub"), 0o755))
+	require.Nil(t, os.WriteFile(filepath.Join(root, "mod", "go.mod"), []byte("module example.com/mod\n\ngo 1.23\n"), 0o644))
+
+	// ## WHEN GetImportPath() of the module and of 'pkg/sub'
+	modPath, modErr := GetImportPath(filepath.Join(root, "mod"))
+	subPath, subErr := GetImportPath(filepath.Join(root, "mod", "pkg", "sub"))
+	// ## THEN paths are 'example.com/mod' and 'example.com/mod/pkg/sub'
+	require.Nil(t, modErr, "must be no error")
+	require.Nil(t, subErr, "must be no error")
+	require.Equal(t, "example.com/mod", modPath)
+	require.Equal(t, "example.com/mod/pkg/sub", subPath)
+
+	// ## WHEN GetImportPath() of a directory without go.mod
+	_, err := GetImportPath(root)
+	// ## THEN there is an error
+	require.NotNil(t, err, "must be an error")
+}
+
+func TestResultsApplySuite(t *testing.T) {
+	// > Test results, Suites
+	// # ApplyTestResults() sets results of "TestRunner/TestXxx" to suite methods of the suite run by "TestRunner"
+	// ## GIVEN a file with the suite 'DBSuite' and its method 'TestInsert' with a subtest 'row'
+	var suiteFile = strings.Join([]string{
+		"package pkg",
+		"",
+		"func (s *DBSuite) TestInsert() {",
+		`	s.Run("row", func() {})`,
+		"}",
+	}, "\n")
+	// - and a file with 'TestDBSuite' which runs the suite by "suite.Run(t, new(DBSuite))"
+	var runnerFile = strings.Join([]string{
+		"package pkg",
+		"",
+		"func TestDBSuite(t *testing.T) {",
+		"	suite.Run(t, new(DBSuite))",
+		"}",
+	}, "\n")
+	suiteData, err := ParseFile("suite_test.go", []byte(suiteFile))
+	require.Nil(t, err, "must be no error")
+	runnerData, err := ParseFile("runner_test.go", []byte(runnerFile))
+	require.Nil(t, err, "must be no error")
+	// - results of 'TestDBSuite/TestInsert' and 'TestDBSuite/TestInsert/row'
+	var results = TestResults{"some/pkg": {
+		"TestDBSuite":                {action: ActionPass},
+		"TestDBSuite/TestInsert":     {action: ActionFail},
+		"TestDBSuite/TestInsert/row": {action: ActionSkip},
+	}}
+
+	// ## WHEN ApplyTestResults() to merged files of the package
+	testData := MergePackages([]*TestData{suiteData, runnerData})[0]
+	ApplyTestResults(testData, results, "some/pkg")
+
+	// ## THEN the suite method and its subtest have results of the runner
+	require.Equal(t, 1, len(testData.methods))
+	require.Equal(t, "DBSuite.TestInsert", testData.methods[0].name)
+	require.Equal(t, TestResult{action: ActionFail}, testData.methods[0].result)
+	require.Equal(t, TestResult{action: ActionSkip}, testData.methods[0].subtests[0].result)
+}
@@ -0,0 +1,87 @@
+package tc2mdc
+
+import "strings"
+
+// Keywords of 'GWT' steps by kind
+var stepKeywords = map[StepKind]string{
+	Given: "GIVEN",
+	When:  "WHEN",
+	Then:  "THEN",
+	And:   "AND",
+	But:   "BUT",
+}
+
+// IsGWT checks the step is a "##" header one, with or without a keyword.
+func (kind StepKind) IsGWT() bool {
+	return kind == GWT || kind.IsKeyword()
+}
+
+// IsKeyword checks the step is a "##" header one starting with a keyword, e.g. "## WHEN".
+func (kind StepKind) IsKeyword() bool {
+	_, ok := stepKeywords[kind]
+	return ok
+}
+
+// Keyword returns the upper case keyword of the kind, e.g. "WHEN", "" if it is not a keyword one.
+func (kind StepKind) Keyword() string {
+	return stepKeywords[kind]
+}
+
+// getGWTStep returns the step of a "##" marker text classified by its leading keyword (any case),
+// the keyword is trimmed from the comment. A text without a keyword is a 'GWT' step.
+func getGWTStep(text string) TestStep {
+	word, rest, _ := strings.Cut(text, " ")
+	for kind, keyword := range stepKeywords {
+		if strings.EqualFold(word, keyword) {
+			return TestStep{kind, strings.TrimSpace(rest)}
+		}
+	}
+	return TestStep{GWT, text}
+}
+
+// getStepText returns the comment of the step after its keyword formatted by "formatKeyword".
+func getStepText(step TestStep, formatKeyword func(string) string) string {
+	if !step.kind.IsKeyword() {
+		return step.comment
+	}
+	if step.comment == "" {
+		return formatKeyword(step.kind.Keyword())
+	}
+	return formatKeyword(step.kind.Keyword()) + " " + step.comment
+}
+
+// ValidateSteps returns issues of the order of keyword steps: a THEN step needs a WHEN one before it,
+// a GIVEN step can't follow a WHEN one until a THEN one, an AND/BUT step needs a keyword one before it.
+func ValidateSteps(steps []TestStep) []string {
+	var issues []string
+	var last StepKind = GWT // the last GIVEN, WHEN or THEN kind
+	for _, step := range steps {
+		switch step.kind {
+		case Given:
+			{
+				if last == When {
+					issues = append(issues, "GIVEN step after WHEN step: "+step.comment)
+				}
+				last = Given
+			}
+		case When:
+			{
+				last = When
+			}
+		case Then:
+			{
+				if last != When && last != Then {
+					issues = append(issues, "THEN step without WHEN step: "+step.comment)
+				}
+				last = Then
+			}
+		case And, But:
+			{
+				if last == GWT {
+					issues = append(issues, step.kind.Keyword()+" step without GIVEN, WHEN or THEN step: "+step.comment)
+				}
+			}
+		}
+	}
+	return issues
+}
@@ -0,0 +1,94 @@
+package tc2mdc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStepKeywords(t *testing.T) {
+	// > Steps
+	// # Parse() classifies "##" steps by the leading keyword in any case and trims it
+	// ## GIVEN Input is
+	var input = []string{
+		"func TestSomething(t *testing.T) {",
+		// - "// ## Given set", "// ## when act", "// ## THEN"
+		OLC + " ## Given set",
+		OLC + " ## when act",
+		OLC + " ## THEN",
+		// - "// ## And more", "// ## BUT not this"
+		OLC + " ## And more",
+		OLC + " ## BUT not this",
+		// - "// ## WHENEVER x" and "// ## Check" without a keyword
+		OLC + " ## WHENEVER x",
+		OLC + " ## Check",
+		"}",
+	}
+
+	// ## WHEN Parse()
+	testData, err := Parse(input)
+	// ## THEN steps are:
+	require.Nil(t, err, "must be no error")
+	require.Equal(t, []TestStep{
+		// - {Given, 'set'}, {When, 'act'}, {Then, ''}
+		{Given, "set"}, {When, "act"}, {Then, ""},
+		// - {And, 'more'}, {But, 'not this'}
+		{And, "more"}, {But, "not this"},
+		// - {GWT, 'WHENEVER x'}, {GWT, 'Check'}
+		{GWT, "WHENEVER x"}, {GWT, "Check"},
+	}, testData.methods[0].steps)
+	// - keyword kinds are 'GWT' ones
+	require.True(t, Given.IsGWT())
+	require.False(t, Common.IsGWT())
+	require.Equal(t, "WHEN", When.Keyword())
+}
+
+func TestStepKeywordsWrite(t *testing.T) {
+	// > Steps
+	// # Writers render keywords of steps in bold
+	// ## GIVEN - testData with 1 method with steps: WHEN 'act `code`', THEN '', 'GWT' 'Check'
+	var testData = &TestData{methods: []TestMethod{{name: "TestA",
+		steps: []TestStep{{When, "act `code`"}, {Then, ""}, {GWT, "Check"}}}}}
+
+	// ## WHEN Write()
+	text := Write(testData)
+	// ## THEN MD text includes headers(4) with bold keywords
+	require.Equal(t, []string{"#### **WHEN** act `code`", "#### **THEN**", "#### Check"}, text[2:5])
+
+	// ## WHEN Write() in HTML
+	text = writers["html"].Write(testData)
+	// ## THEN HTML text includes the keyword in "strong"
+	require.Contains(t, text, `<h4 class="step"><strong>WHEN</strong> act <code>code</code></h4>`)
+
+	// ## WHEN Write() in AsciiDoc
+	text = writers["adoc"].Write(testData)
+	// ## THEN AsciiDoc text includes the keyword in bold
+	require.Contains(t, text, "==== *WHEN* act `code`")
+
+	// ## WHEN Write() in text
+	text = writers["txt"].Write(testData)
+	// ## THEN text includes the keyword as is
+	require.Contains(t, text, "WHEN act code")
+}
+
+func TestValidateSteps(t *testing.T) {
+	// > Steps
+	// # ValidateSteps() returns issues of the order of keyword steps
+	// ## WHEN ValidateSteps() of GIVEN, WHEN, THEN, AND, WHEN, THEN, 'GWT', BUT steps
+	issues := ValidateSteps([]TestStep{{Given, "a"}, {When, "b"}, {Then, "c"}, {And, "d"},
+		{When, "e"}, {Then, "f"}, {GWT, "g"}, {But, "h"}})
+	// ## THEN there are no issues
+	require.Nil(t, issues)
+
+	// ## WHEN ValidateSteps() of AND, GIVEN, THEN, WHEN, GIVEN steps
+	issues = ValidateSteps([]TestStep{{And, "a"}, {Given, "b"}, {Then, "c"}, {When, "d"}, {Given, "e"}})
+	// ## THEN there are 3 issues:
+	require.Equal(t, []string{
+		// - AND without a keyword step before it
+		"AND step without GIVEN, WHEN or THEN step: a",
+		// - THEN without WHEN
+		"THEN step without WHEN step: c",
+		// - GIVEN after WHEN
+		"GIVEN step after WHEN step: e",
+	}, issues)
+}
@@ -0,0 +1,116 @@
+package tc2mdc
+
+import (
+	"go/ast"
+	"go/token"
+)
+
+// Setup methods of a testify suite, their steps are the background of all tests of the suite
+var suiteSetups = []string{"SetupSuite", "SetupTest"}
+
+// TestSuite is a testify suite: a type with "func (s *Suite) TestXxx()" test methods.
+type TestSuite struct {
+	name       string
+	background []TestStep // steps of "SetupSuite" and then "SetupTest" methods
+}
+
+// Name returns the name of the suite type.
+func (suite TestSuite) Name() string {
+	return suite.name
+}
+
+// Background returns the steps shared by all tests of the suite, 'nil' if there are no setup markers.
+func (suite TestSuite) Background() []TestStep {
+	return append([]TestStep(nil), suite.background...)
+}
+
+// Suite returns the name of the testify suite of a suite method, empty for a test func.
+func (method TestMethod) Suite() string {
+	return method.suite
+}
+
+// Suites returns the testify suites in the order of the source.
+func (data *TestData) Suites() []TestSuite {
+	return append([]TestSuite(nil), data.suites...)
+}
+
+// getSuiteReceiver returns the receiver type of a method without params and results as testify runs them:
+// "func (s *Suite) Xxx()".
+func getSuiteReceiver(funcDecl *ast.FuncDecl) (string, bool) {
+	if funcDecl.Recv == nil || len(funcDecl.Recv.List) != 1 || len(funcDecl.Type.Params.List) != 0 ||
+		funcDecl.Type.Results != nil {
+		return "", false
+	}
+	recvType := funcDecl.Recv.List[0].Type
+	if star, ok := recvType.(*ast.StarExpr); ok {
+		recvType = star.X
+	}
+	ident, ok := recvType.(*ast.Ident)
+	if !ok {
+		return "", false // generic suites are not supported
+	}
+	return ident.Name, true
+}
+
+// getSuite returns the suite of the data by name, it is added if there is no such suite.
+func (data *TestData) getSuite(name string) *TestSuite {
+	for i := range data.suites {
+		if data.suites[i].name == name {
+			return &data.suites[i]
+		}
+	}
+	data.suites = append(data.suites, TestSuite{name: name})
+	return &data.suites[len(data.suites)-1]
+}
+
+// addBackground adds steps of a setup method to the background, "SetupSuite" steps are before "SetupTest" ones.
+func (suite *TestSuite) addBackground(setup string, steps []TestStep) {
+	if setup == suiteSetups[0] {
+		suite.background = append(append([]TestStep(nil), steps...), suite.background...)
+		return
+	}
+	suite.background = append(suite.background, steps...)
+}
+
+// addSuiteRunner adds the test func which runs the suite, results of suite methods are reported under its name.
+func (data *TestData) addSuiteRunner(suite string, runner string) {
+	if data.suiteRunners == nil {
+		data.suiteRunners = make(map[string][]string)
+	}
+	data.suiteRunners[suite] = append(data.suiteRunners[suite], runner)
+}
+
+// getSuiteRuns returns names of suite types run in the body by "suite.Run(t, new(Suite))"
+// or "suite.Run(t, &Suite{})" calls, a suite in a variable is not found.
+func getSuiteRuns(body *ast.BlockStmt) []string {
+	var suites []string
+	ast.Inspect(body, func(node ast.Node) bool {
+		call, ok := node.(*ast.CallExpr)
+		if !ok || len(call.Args) != 2 {
+			return true
+		}
+		if fun, ok := call.Fun.(*ast.SelectorExpr); !ok || fun.Sel.Name != "Run" {
+			return true
+		}
+		switch arg := call.Args[1].(type) {
+		case *ast.CallExpr:
+			{
+				if fun, ok := arg.Fun.(*ast.Ident); ok && fun.Name == "new" && len(arg.Args) == 1 {
+					if suiteType, ok := arg.Args[0].(*ast.Ident); ok {
+						suites = append(suites, suiteType.Name)
+					}
+				}
+			}
+		case *ast.UnaryExpr:
+			{
+				if lit, ok := arg.X.(*ast.CompositeLit); ok && arg.Op == token.AND {
+					if suiteType, ok := lit.Type.(*ast.Ident); ok {
+						suites = append(suites, suiteType.Name)
+					}
+				}
+			}
+		}
+		return true
+	})
+	return suites
+}
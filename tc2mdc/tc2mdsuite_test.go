@@ -0,0 +1,161 @@
+package tc2mdc
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSuiteMethods(t *testing.T) {
+	// > Suites, Go AST
+	// # ParseFile() finds test methods of testify suites and steps of their setup methods as the background
+	// ## GIVEN Input is
+	var input = strings.Join([]string{
+		"package somePackage",
+		"",
+		"type DBSuite struct {",
+		"	suite.Suite",
+		"}",
+		"",
+		// - "SetupTest" after "SetupSuite" in the source
+		"func (s *DBSuite) SetupTest() {",
+		"	// ## GIVEN empty table",
+		"}",
+		"",
+		"func (s *DBSuite) SetupSuite() {",
+		"	// ## GIVEN connected DB",
+		"}",
+		"",
+		// - "func (s *DBSuite) TestInsert()" with a scenario and a subtest
+		"// # Insert a row",
+		"func (s *DBSuite) TestInsert() {",
+		"	// ## WHEN insert",
+		`	s.Run("twice", func() {`,
+		"		// ## THEN error",
+		"	})",
+		"}",
+		"",
+		// - not suite tests: a helper method and a method with params
+		"func (s *DBSuite) helper() {}",
+		"func (s *DBSuite) TestParam(t *testing.T) {}",
+	}, "\n")
+
+	// ## WHEN ParseFile()
+	testData, err := ParseFile("some_test.go", []byte(input))
+	// ## THEN the method is 'DBSuite.TestInsert' of the suite 'DBSuite' with its subtest
+	require.Nil(t, err, "must be no error")
+	require.Equal(t, 1, len(testData.Methods()))
+	method := testData.Methods()[0]
+	require.Equal(t, "DBSuite.TestInsert", method.Name())
+	require.Equal(t, "DBSuite", method.Suite())
+	require.Equal(t, "Insert a row", method.Scenario())
+	require.Equal(t, []TestStep{{When, "insert"}}, method.Steps())
+	require.Equal(t, "DBSuite.TestInsert/twice", method.Subtests()[0].Name())
+	// ## AND the suite has the background: steps of "SetupSuite" and then of "SetupTest"
+	require.Equal(t, 1, len(testData.Suites()))
+	require.Equal(t, "DBSuite", testData.Suites()[0].Name())
+	require.Equal(t, []TestStep{{Given, "connected DB"}, {Given, "empty table"}}, testData.Suites()[0].Background())
+}
+
+func TestSuiteRunner(t *testing.T) {
+	// > Suites, Lint
+	// # A test func running a suite by "suite.Run()" is not a test of its own
+	// ## GIVEN Input is a documented suite method and 'TestDBSuite' running the suite
+	var input = strings.Join([]string{
+		"package somePackage",
+		"",
+		"// # Insert a row",
+		"func (s *DBSuite) TestInsert() {",
+		"	// ## WHEN insert",
+		"}",
+		"",
+		"func TestDBSuite(t *testing.T) {",
+		"	suite.Run(t, &DBSuite{})",
+		"}",
+	}, "\n")
+
+	// ## WHEN ParseFile() and Lint()
+	testData, err := ParseFile("some_test.go", []byte(input))
+	issues, lintErr := Lint("some_test.go", []byte(input))
+	// ## THEN the only method is the suite method
+	require.Nil(t, err, "must be no error")
+	require.Equal(t, 1, len(testData.Methods()))
+	require.Equal(t, "DBSuite.TestInsert", testData.Methods()[0].Name())
+	// ## AND there are no issues of the runner
+	require.Nil(t, lintErr, "must be no error")
+	require.Nil(t, issues, "issues must be nil")
+}
+
+func TestWriteSuites(t *testing.T) {
+	// > Suites, Write to MD
+	// # Write() puts tests of a suite in its section after tests with the background of the suite
+	// ## GIVEN - testData: a test, a test of 'DBSuite' and the suite with a background step
+	var testData = &TestData{
+		methods: []TestMethod{{name: "DBSuite.TestInsert", suite: "DBSuite"}, {name: "TestA"}},
+		suites:  []TestSuite{{name: "DBSuite", background: []TestStep{{Given, "connected DB"}}}},
+	}
+	fillTOC(testData)
+
+	// ## WHEN Write()
+	mdText := Write(testData)
+
+	// ## THEN MD text is:
+	require.Equal(t, []string{
+		"1. [`TestA`](#testa)",
+		"2. [`DBSuite.TestInsert`](#dbsuitetestinsert)",
+		"",
+		"### Tests",
+		"---",
+		"#### `TestA`",
+		"",
+		"[top](#top)",
+		// - the section of the suite with the background steps one level deeper
+		"### Suite DBSuite",
+		"#### Background",
+		"##### **GIVEN** connected DB",
+		"---",
+		"#### `DBSuite.TestInsert`",
+		"",
+		"[top](#top)",
+	}, mdText)
+}
+
+func TestMergeSuites(t *testing.T) {
+	// > Suites, Packages
+	// # MergePackages() merges suites of the same name and their backgrounds
+	// ## GIVEN two files of a package with 'DBSuite' and its setup steps
+	files := []*TestData{
+		{packageName: "pkg", suites: []TestSuite{{name: "DBSuite", background: []TestStep{{Given, "connected DB"}}}}},
+		{packageName: "pkg", suites: []TestSuite{{name: "DBSuite", background: []TestStep{{Given, "empty table"}}},
+			{name: "APISuite"}}},
+	}
+
+	// ## WHEN MergePackages()
+	packages := MergePackages(files)
+
+	// ## THEN the package has 'DBSuite' with both steps and 'APISuite'
+	require.Equal(t, []TestSuite{
+		{name: "DBSuite", background: []TestStep{{Given, "connected DB"}, {Given, "empty table"}}},
+		{name: "APISuite"},
+	}, packages[0].Suites())
+}
+
+func TestSuiteMethodsOfLines(t *testing.T) {
+	// > Suites
+	// # Parse() of code without "package" finds test methods of suites line by line
+	// ## GIVEN Input is
+	var input = []string{
+		"func (s *DBSuite) TestInsert() {",
+		"	// ## WHEN insert",
+		"}",
+	}
+
+	// ## WHEN Parse()
+	testData, err := Parse(input)
+	// ## THEN the method is 'DBSuite.TestInsert' of the suite 'DBSuite'
+	require.Nil(t, err, "must be no error")
+	require.Equal(t, "DBSuite.TestInsert", testData.methods[0].name)
+	require.Equal(t, []TestStep{{When, "insert"}}, testData.methods[0].steps)
+	require.Equal(t, []TestSuite{{name: "DBSuite"}}, testData.suites)
+}
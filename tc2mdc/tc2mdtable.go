@@ -0,0 +1,195 @@
+package tc2mdc
+
+import (
+	"go/ast"
+	"go/token"
+	"strconv"
+	"strings"
+)
+
+// TestCase is a row of a table-driven test.
+type TestCase struct {
+	name   string
+	fields []TestField
+}
+
+// TestField is a field of a table-driven test row with its value as source code.
+type TestField struct {
+	name  string
+	value string
+}
+
+// Name returns the name of the case, the value of the "t.Run()" name field or "case N".
+func (testCase TestCase) Name() string {
+	return testCase.name
+}
+
+// Fields returns the fields of the row in the order of the source.
+func (testCase TestCase) Fields() []TestField {
+	return append([]TestField(nil), testCase.fields...)
+}
+
+// Name returns the name of the struct field.
+func (field TestField) Name() string {
+	return field.name
+}
+
+// Value returns the source code of the value.
+func (field TestField) Value() string {
+	return field.value
+}
+
+// testTable is a "[]struct{...}{...}" literal assigned to a variable in a test func.
+type testTable struct {
+	fields []string // struct field names in order
+	rows   *ast.CompositeLit
+}
+
+// parseTableCases returns rows of a table-driven test: a "[]struct{...}{...}" table ranged over
+// by a loop, the name of a row is the field passed to "t.Run(tt.name, ...)" in the loop.
+// Tables and loops inside of subtest bodies are cases of the subtests, so they are skipped.
+func parseTableCases(body *ast.BlockStmt, subtestBodies []*ast.BlockStmt, fset *token.FileSet, src []byte) []TestCase {
+	tables := getTestTables(body, subtestBodies)
+	if len(tables) == 0 {
+		return nil
+	}
+
+	var cases []TestCase
+	ast.Inspect(body, func(node ast.Node) bool {
+		if node == nil || isInsideBlocks(subtestBodies, node.Pos()) {
+			return false
+		}
+		rangeStmt, ok := node.(*ast.RangeStmt)
+		if !ok || cases != nil {
+			return cases == nil
+		}
+		tableIdent, ok := rangeStmt.X.(*ast.Ident)
+		if !ok || tables[tableIdent.Name] == nil {
+			return true
+		}
+		var nameField string
+		if rowIdent, ok := rangeStmt.Value.(*ast.Ident); ok {
+			nameField = getRunNameField(rangeStmt.Body, rowIdent.Name)
+		}
+		cases = getTableCases(tables[tableIdent.Name], nameField, fset, src)
+		return false
+	})
+	return cases
+}
+
+// getTestTables returns "[]struct{...}{...}" literals by names of variables they are assigned to.
+func getTestTables(body *ast.BlockStmt, subtestBodies []*ast.BlockStmt) map[string]*testTable {
+	tables := make(map[string]*testTable)
+	addTable := func(name *ast.Ident, value ast.Expr) {
+		rows, ok := value.(*ast.CompositeLit)
+		if !ok {
+			return
+		}
+		arrayType, ok := rows.Type.(*ast.ArrayType)
+		if !ok {
+			return
+		}
+		structType, ok := arrayType.Elt.(*ast.StructType)
+		if !ok {
+			return
+		}
+		table := &testTable{rows: rows}
+		for _, field := range structType.Fields.List {
+			for _, fieldName := range field.Names {
+				table.fields = append(table.fields, fieldName.Name)
+			}
+		}
+		tables[name.Name] = table
+	}
+
+	ast.Inspect(body, func(node ast.Node) bool {
+		if node == nil || isInsideBlocks(subtestBodies, node.Pos()) {
+			return false
+		}
+		switch stmt := node.(type) {
+		case *ast.AssignStmt:
+			{
+				for i, lhs := range stmt.Lhs {
+					if ident, ok := lhs.(*ast.Ident); ok && i < len(stmt.Rhs) {
+						addTable(ident, stmt.Rhs[i])
+					}
+				}
+			}
+		case *ast.ValueSpec:
+			{
+				for i, ident := range stmt.Names {
+					if i < len(stmt.Values) {
+						addTable(ident, stmt.Values[i])
+					}
+				}
+			}
+		}
+		return true
+	})
+	return tables
+}
+
+// getRunNameField returns the field of the row variable passed as a name to "t.Run(row.field, ...)".
+func getRunNameField(body *ast.BlockStmt, rowName string) string {
+	var nameField string
+	ast.Inspect(body, func(node ast.Node) bool {
+		call, ok := node.(*ast.CallExpr)
+		if !ok || nameField != "" || len(call.Args) != 2 {
+			return nameField == ""
+		}
+		if fun, ok := call.Fun.(*ast.SelectorExpr); !ok || fun.Sel.Name != "Run" {
+			return true
+		}
+		if arg, ok := call.Args[0].(*ast.SelectorExpr); ok {
+			if ident, ok := arg.X.(*ast.Ident); ok && ident.Name == rowName {
+				nameField = arg.Sel.Name
+			}
+		}
+		return true
+	})
+	return nameField
+}
+
+// getTableCases converts rows of the table to test cases, a row without a name is named by its number.
+func getTableCases(table *testTable, nameField string, fset *token.FileSet, src []byte) []TestCase {
+	var cases []TestCase
+	for i, elt := range table.rows.Elts {
+		row, ok := elt.(*ast.CompositeLit)
+		if !ok {
+			continue
+		}
+		testCase := TestCase{name: "case " + strconv.Itoa(i+1)}
+		for j, value := range row.Elts {
+			var fieldName string
+			if keyValue, ok := value.(*ast.KeyValueExpr); ok {
+				if key, ok := keyValue.Key.(*ast.Ident); ok {
+					fieldName = key.Name
+				}
+				value = keyValue.Value
+			} else if j < len(table.fields) {
+				fieldName = table.fields[j]
+			}
+
+			text := getSourceText(value, fset, src)
+			if fieldName != "" && fieldName == nameField {
+				if name, err := strconv.Unquote(text); err == nil {
+					text = name
+				}
+				testCase.name = text
+				continue
+			}
+			testCase.fields = append(testCase.fields, TestField{fieldName, text})
+		}
+		cases = append(cases, testCase)
+	}
+	return cases
+}
+
+// getSourceText returns the source code of the expression, multi-line code is joined into one line.
+func getSourceText(expr ast.Expr, fset *token.FileSet, src []byte) string {
+	text := string(src[fset.Position(expr.Pos()).Offset:fset.Position(expr.End()).Offset])
+	if strings.Contains(text, "\n") {
+		text = strings.Join(strings.Fields(text), " ")
+	}
+	return text
+}
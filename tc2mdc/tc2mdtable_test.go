@@ -0,0 +1,123 @@
+package tc2mdc
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTableKeyedRows(t *testing.T) {
+	// > Table-driven, Go AST
+	// # ParseFile() returns rows of a "[]struct" table ranged with "t.Run(tt.name, ...)" as cases
+	// ## GIVEN Input is a test func with
+	var input = strings.Join([]string{
+		"package somePackage",
+		"func TestSomething(t *testing.T) {",
+		// - a table with fields "name", "in", "want" and 2 keyed rows
+		"	tests := []struct {",
+		"		name string",
+		"		in   []int",
+		"		want int",
+		"	}{",
+		`		{name: "empty", want: 0},`,
+		`		{name: "two items", in: []int{1,`,
+		`			2}, want: 3},`,
+		"	}",
+		// - a loop with "t.Run(tt.name, ...)"
+		"	for _, tt := range tests {",
+		"		t.Run(tt.name, func(t *testing.T) {})",
+		"	}",
+		"}",
+	}, "\n")
+
+	// ## WHEN ParseFile()
+	testData, err := ParseFile("some_test.go", []byte(input))
+	// ## THEN output data has 1 method with 2 "cases":
+	require.Nil(t, err, "must be no error")
+	require.Equal(t, 1, len(testData.methods))
+	require.Equal(t, []TestCase{
+		// - "name" = 'empty', "fields" = {'want', '0'}
+		{name: "empty", fields: []TestField{{"want", "0"}}},
+		// - "name" = 'two items', "fields" = {'in', '[]int{1, 2}'}, {'want', '3'} // multi-line values joined
+		{name: "two items", fields: []TestField{{"in", "[]int{1, 2}"}, {"want", "3"}}},
+	}, testData.methods[0].cases)
+}
+
+func TestTableUnkeyedRows(t *testing.T) {
+	// > Table-driven, Go AST
+	// # ParseFile() returns cases of unkeyed rows with fields named by the struct, a loop without "t.Run" numbers cases
+	// ## GIVEN Input is a test func with
+	var input = strings.Join([]string{
+		"package somePackage",
+		"func TestSomething(t *testing.T) {",
+		// - a table declared by "var" with fields "a", "b" and 2 unkeyed rows
+		"	var cases = []struct{ a, b string }{",
+		`		{"x", "y"},`,
+		`		{"z", "w"},`,
+		"	}",
+		// - a loop without "t.Run"
+		"	for _, c := range cases {",
+		"		_ = c",
+		"	}",
+		"}",
+	}, "\n")
+
+	// ## WHEN ParseFile()
+	testData, err := ParseFile("some_test.go", []byte(input))
+	// ## THEN output data has 1 method with 2 "cases":
+	require.Nil(t, err, "must be no error")
+	require.Equal(t, []TestCase{
+		// - "name" = 'case 1', "fields" = {'a', '"x"'}, {'b', '"y"'}
+		{name: "case 1", fields: []TestField{{"a", `"x"`}, {"b", `"y"`}}},
+		// - "name" = 'case 2', "fields" = {'a', '"z"'}, {'b', '"w"'}
+		{name: "case 2", fields: []TestField{{"a", `"z"`}, {"b", `"w"`}}},
+	}, testData.methods[0].cases)
+}
+
+func TestTableWithoutLoop(t *testing.T) {
+	// > Table-driven, Go AST
+	// # ParseFile() returns no cases for a "[]struct" table which is not ranged over
+	// ## GIVEN Input is a test func with a table and no loop
+	var input = strings.Join([]string{
+		"package somePackage",
+		"func TestSomething(t *testing.T) {",
+		`	tests := []struct{ name string }{{name: "x"}}`,
+		"	_ = tests",
+		"}",
+	}, "\n")
+
+	// ## WHEN ParseFile()
+	testData, err := ParseFile("some_test.go", []byte(input))
+	// ## THEN output data has 1 method with "cases" = 'nil'
+	require.Nil(t, err, "must be no error")
+	require.Nil(t, testData.methods[0].cases, "cases must be nil")
+}
+
+func TestTableOfSubtest(t *testing.T) {
+	// > Table-driven, Go AST
+	// # ParseFile() returns a table inside of a "t.Run" subtest as cases of the subtest only
+	// ## GIVEN Input is a test func with a subtest 'child' with a table and a loop
+	var input = strings.Join([]string{
+		"package somePackage",
+		"func TestParent(t *testing.T) {",
+		`	t.Run("child", func(t *testing.T) {`,
+		"		tests := []struct{ name string }{",
+		`			{name: "one"},`,
+		"		}",
+		"		for _, tt := range tests {",
+		"			t.Run(tt.name, func(t *testing.T) {})",
+		"		}",
+		"	})",
+		"}",
+	}, "\n")
+
+	// ## WHEN ParseFile()
+	testData, err := ParseFile("some_test.go", []byte(input))
+	// ## THEN the parent has "cases" = 'nil'
+	require.Nil(t, err, "must be no error")
+	require.Nil(t, testData.methods[0].cases, "cases must be nil")
+	// ## AND the subtest has the case 'one'
+	require.Equal(t, 1, len(testData.methods[0].subtests))
+	require.Equal(t, []TestCase{{name: "one"}}, testData.methods[0].subtests[0].cases)
+}
@@ -0,0 +1,126 @@
+package tc2mdc
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Separator of tests in plain text
+const textSeparator = "----------------------------------------"
+
+// textMarkup is the plain text format, "`" of code spans is removed.
+type textMarkup struct{}
+
+func (textMarkup) appendDocument(title string, body []string, text *[]string) {
+	*text = append(*text, body...)
+}
+
+func (textMarkup) appendTitle(title string, text *[]string) {
+	title = getPlainText(title)
+	*text = append(*text, title, strings.Repeat("=", utf8.RuneCountInString(title)))
+}
+
+func (textMarkup) appendPackage(packageName string, text *[]string) {
+	*text = append(*text, "Package "+packageName)
+}
+
+func (textMarkup) appendResultsSummary(counts map[string]int, text *[]string) {
+	*text = append(*text, getResultsSummary(counts, getTextResultBadge))
+}
+
+func (textMarkup) appendTOC(lines []TOCLine, text *[]string) {
+	*text = append(*text, "")
+	for _, line := range lines {
+		*text = append(*text, strconv.Itoa(line.index+1)+". "+getPlainText(line.caption))
+	}
+}
+
+func (textMarkup) appendSection(section string, text *[]string) {
+	*text = append(*text, "", section, strings.Repeat("-", utf8.RuneCountInString(section)))
+}
+
+func (textMarkup) appendBackground(text *[]string) {
+	*text = append(*text, "Background:")
+}
+
+func (textMarkup) appendFunc(name string, anchor string, depth int, text *[]string) {
+	if depth == 0 {
+		*text = append(*text, "", textSeparator)
+	} else {
+		*text = append(*text, "")
+	}
+	*text = append(*text, name)
+}
+
+func (textMarkup) appendFuncInfo(result TestResult, gitLink string, text *[]string) {
+	if result.action != "" {
+		*text = append(*text, "Result: "+getTextResultBadge(result.action)+" "+getElapsed(result))
+	}
+	if gitLink != "" {
+		*text = append(*text, "Source: "+gitLink)
+	}
+}
+
+func (textMarkup) appendTags(tags []string, text *[]string) {
+	*text = append(*text, "Tags: "+strings.Join(tags, ", "))
+}
+
+func (textMarkup) appendScenario(scenario string, depth int, text *[]string) {
+	*text = append(*text, "Scenario: "+getPlainText(scenario))
+}
+
+func (textMarkup) appendDescription(description []string, text *[]string) {
+	for _, line := range description {
+		*text = append(*text, strings.TrimRight("  "+getPlainText(line), " "))
+	}
+}
+
+func (textMarkup) appendSteps(steps []TestStep, depth int, text *[]string) {
+	for _, step := range steps {
+		if step.kind.IsGWT() {
+			*text = append(*text, getPlainText(getStepText(step, func(keyword string) string { return keyword })))
+			continue
+		}
+		*text = append(*text, strings.Repeat("  ", int(step.kind))+"- "+getPlainText(step.comment))
+	}
+}
+
+func (textMarkup) appendCase(testCase TestCase, depth int, text *[]string) {
+	*text = append(*text, "Case: "+testCase.name)
+	for _, field := range testCase.fields {
+		*text = append(*text, "  "+field.name+" = "+field.value)
+	}
+}
+
+func (textMarkup) appendFailureOutput(output []string, text *[]string) {
+	*text = append(*text, "Failure output:")
+	for _, line := range output {
+		*text = append(*text, "  "+line)
+	}
+}
+
+func (textMarkup) appendOutput(output []string, text *[]string) {
+	*text = append(*text, "Output:")
+	for _, line := range output {
+		*text = append(*text, "  "+line)
+	}
+}
+
+func (textMarkup) appendFuncEnd(topAnchor string, text *[]string) {}
+
+func (textMarkup) appendIndex(items []indexItem, text *[]string) {
+	*text = append(*text, "Packages")
+	for _, item := range items {
+		*text = append(*text, "- "+getPlainText(item.caption)+": "+item.link+" - "+getTestsCount(item.count))
+	}
+}
+
+func getTextResultBadge(action string) string {
+	return strings.ToUpper(action)
+}
+
+// getPlainText returns the text without "`" of code spans.
+func getPlainText(text string) string {
+	return strings.ReplaceAll(text, "`", "")
+}
@@ -0,0 +1,53 @@
+package tc2mdc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTextMethod(t *testing.T) {
+	// > Write to text
+	// # Text writer returns plain lines with labels, indented steps and without "`" of code spans
+	// ## GIVEN - testData: "title" = 'Title', "packageName" = 'pkg' with 1 method 'TestA':
+	var testData = &TestData{title: "Title", packageName: "pkg"}
+	testData.methods = []TestMethod{{
+		name: "TestA",
+		// - "tags" = 'T1', 'T2', "scenario" = 'Returns `nil`'
+		tags:     []string{"T1", "T2"},
+		scenario: "Returns `nil`",
+		// - "steps": 'GWT' 'WHEN act', common 'Step1', indented 'Step2'
+		steps: []TestStep{{GWT, "WHEN act"}, {Common, "Step1"}, {Indented, "Step2"}},
+		// - "cases": 'c1' with field {'in', '1'}
+		cases: []TestCase{{name: "c1", fields: []TestField{{"in", "1"}}}},
+		// - 1 subtest 'TestA/child' passed
+		subtests: []TestMethod{{name: "TestA/child", result: TestResult{action: ActionPass}}},
+	}}
+	writer, err := GetWriter("txt")
+	require.Nil(t, err, "must be no error")
+
+	// ## WHEN Write()
+	text := writer.Write(testData)
+
+	// ## THEN - text is:
+	require.Equal(t, []string{
+		"Title",
+		"=====",
+		"Package pkg",
+		"",
+		textSeparator,
+		"TestA",
+		"Tags: T1, T2",
+		"Scenario: Returns nil",
+		"WHEN act",
+		"  - Step1",
+		"    - Step2",
+		"Case: c1",
+		"  in = 1",
+		"",
+		"TestA/child",
+		"Result: PASS 0.00s",
+	}, text)
+	// - extension is '.txt'
+	require.Equal(t, ".txt", writer.Extension())
+}
@@ -1,86 +1,498 @@
 package tc2mdc
 
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Writer renders test data into lines of a document in some format.
+type Writer interface {
+	// Write returns the document of the test data.
+	Write(data *TestData) []string
+	// WriteAll returns one document of all the test data, e.g. of several packages.
+	WriteAll(data []*TestData) []string
+	// WriteIndex returns the document with a link per package document named by its title or package name,
+	// "links" are paths to the package documents in the same order as "packages". It is 'nil' if the format
+	// has no index.
+	WriteIndex(packages []*TestData, links []string) []string
+	// Extension returns the file extension of documents, e.g. ".md".
+	Extension() string
+}
+
+// markup renders elements of a document in a format, docWriter puts them in order.
+// Headers of a subtest are deeper than the parent ones by its "depth".
+type markup interface {
+	appendDocument(title string, body []string, text *[]string)
+	appendTitle(title string, text *[]string)
+	appendPackage(packageName string, text *[]string)
+	appendResultsSummary(counts map[string]int, text *[]string)
+	appendTOC(lines []TOCLine, text *[]string)
+	appendSection(section string, text *[]string)
+	appendBackground(text *[]string)
+	appendFunc(name string, anchor string, depth int, text *[]string)
+	appendFuncInfo(result TestResult, gitLink string, text *[]string)
+	appendTags(tags []string, text *[]string)
+	appendScenario(scenario string, depth int, text *[]string)
+	appendDescription(description []string, text *[]string)
+	appendSteps(steps []TestStep, depth int, text *[]string)
+	appendCase(testCase TestCase, depth int, text *[]string)
+	appendFailureOutput(output []string, text *[]string)
+	appendOutput(output []string, text *[]string)
+	appendFuncEnd(topAnchor string, text *[]string)
+	appendIndex(items []indexItem, text *[]string)
+}
+
+// Sections of test funcs by kind in the order of the document, tests of suites follow the "Tests" section
+var kindSections = []struct {
+	kind  FuncKind
+	title string
+}{{TestFunc, "Tests"}, {BenchmarkFunc, "Benchmarks"}, {FuzzFunc, "Fuzz tests"}, {ExampleFunc, "Examples"}}
+
+// funcSection is a section of a document: funcs of a kind or tests of a suite.
+type funcSection struct {
+	title   string
+	suite   *TestSuite // 'nil' if it is not a section of a suite
+	methods []TestMethod
+}
+
+// indexItem is a link to a package document in the index.
+type indexItem struct {
+	caption string
+	link    string
+	count   int // tests count
+}
+
+// docWriter is the Writer of a markup format.
+type docWriter struct {
+	markup    markup
+	extension string
+}
+
+var writers = map[string]Writer{
+	"md":      &docWriter{markdownMarkup{}, ".md"},
+	"html":    &docWriter{htmlMarkup{}, ".html"},
+	"adoc":    &docWriter{asciiDocMarkup{}, ".adoc"},
+	"txt":     &docWriter{textMarkup{}, ".txt"},
+	"gherkin": gherkinWriter{},
+	"json":    jsonWriter{},
+	"yaml":    yamlWriter{},
+}
+
+// GetWriter returns the writer of the format, one of Formats().
+func GetWriter(format string) (Writer, error) {
+	writer, ok := writers[format]
+	if !ok {
+		return nil, errors.New("unknown format: " + format)
+	}
+	return writer, nil
+}
+
+// Formats returns sorted names of all formats.
+func Formats() []string {
+	var formats []string
+	for format := range writers {
+		formats = append(formats, format)
+	}
+	sort.Strings(formats)
+	return formats
+}
+
+// Write returns MD text of the test data.
 func Write(data *TestData) []string {
+	return writers["md"].Write(data)
+}
+
+// WriteIndex returns MD text of the index document, see Writer.WriteIndex().
+func WriteIndex(packages []*TestData, links []string) []string {
+	return writers["md"].WriteIndex(packages, links)
+}
+
+func (writer *docWriter) Write(data *TestData) []string {
 	if data == nil {
 		return nil
 	}
-	var mdText []string
+	return writer.WriteAll([]*TestData{data})
+}
+
+func (writer *docWriter) WriteAll(data []*TestData) []string {
+	var title string
+	var body []string
+	for _, packageData := range data {
+		if packageData == nil {
+			continue
+		}
+		if title == "" {
+			title = packageData.title
+		}
+		writer.appendBody(packageData, &body)
+	}
+	if body == nil {
+		return nil
+	}
+	var text []string
+	writer.markup.appendDocument(title, body, &text)
+	return text
+}
+
+func (writer *docWriter) WriteIndex(packages []*TestData, links []string) []string {
+	var items []indexItem
+	for i, data := range packages {
+		caption := data.title
+		if caption == "" {
+			caption = "`" + data.packageName + "`"
+		}
+		items = append(items, indexItem{caption, links[i], len(data.methods)})
+	}
+	var body, text []string
+	writer.markup.appendIndex(items, &body)
+	writer.markup.appendDocument("", body, &text)
+	return text
+}
+
+func (writer *docWriter) Extension() string {
+	return writer.extension
+}
 
+func (writer *docWriter) appendBody(data *TestData, text *[]string) {
+	m := writer.markup
+	if data.title != "" {
+		m.appendTitle(data.title, text)
+	}
 	if data.packageName != "" {
-		mdText = append(mdText, "## `"+data.packageName+"`")
+		m.appendPackage(data.packageName, text)
+	}
+	if counts := countResults(data.methods); counts != nil {
+		m.appendResultsSummary(counts, text)
+	}
+	sections := getFuncSections(data)
+	if len(data.toc) != 0 {
+		m.appendTOC(getSectionsTOC(data.toc, sections), text)
 	}
 
-	for _, method := range data.methods {
-		appendFunc(method.name, &mdText)
-		appendTags(method.tags, &mdText)
-		appendScenario(method.scenario, &mdText)
-		appendSteps(method.steps, &mdText)
-		appendFuncEnd(data.packageName, &mdText)
+	if data.background != nil {
+		m.appendBackground(text)
+		m.appendSteps(data.background, 1, text)
 	}
 
-	return mdText
+	hasSections := len(sections) > 1 || (len(sections) == 1 && sections[0].suite != nil)
+	for _, section := range sections {
+		if hasSections {
+			m.appendSection(section.title, text)
+		}
+		if section.suite != nil && section.suite.background != nil {
+			m.appendBackground(text)
+			m.appendSteps(section.suite.background, 1, text)
+		}
+		for _, method := range section.methods {
+			m.appendFunc(method.name, getAnchor("`"+method.name+"`"), 0, text)
+			m.appendFuncInfo(method.result, data.toc[method.name].gitLink, text)
+			writer.appendMethodBody(method, 0, text)
+			m.appendFuncEnd(getTopAnchor(data.packageName), text)
+		}
+	}
 }
 
-func appendSteps(steps []TestStep, mdText *[]string) {
-	for _, step := range steps {
-		*mdText = append(*mdText, getStepPrefix(step.kind)+step.comment)
+// appendMethodBody adds tags, scenario, description, steps, cases and subtests of the method.
+func (writer *docWriter) appendMethodBody(method TestMethod, depth int, text *[]string) {
+	m := writer.markup
+	if method.tags != nil {
+		m.appendTags(method.tags, text)
+	}
+	if method.scenario != "" {
+		m.appendScenario(method.scenario, depth, text)
+	}
+	if method.description != nil {
+		m.appendDescription(method.description, text)
+	}
+	if method.steps != nil {
+		m.appendSteps(method.steps, depth, text)
+	}
+	for _, testCase := range method.cases {
+		m.appendCase(testCase, depth, text)
+	}
+	if method.output != nil {
+		m.appendOutput(method.output, text)
+	}
+	if method.result.action == ActionFail && len(method.result.output) != 0 {
+		m.appendFailureOutput(method.result.output, text)
+	}
+	for _, subtest := range method.subtests {
+		m.appendFunc(subtest.name, getAnchor("`"+subtest.name+"`"), depth+1, text)
+		m.appendFuncInfo(subtest.result, "", text)
+		writer.appendMethodBody(subtest, depth+1, text)
 	}
 }
 
-func getStepPrefix(kind int) string {
-	switch kind {
-	case GWT:
-		{
-			return "#### "
+// getFuncSections returns sections of the data in the order of the document: tests, tests of each suite
+// (with its background even if it has no tests), benchmarks, fuzz tests and examples. Empty sections are skipped.
+func getFuncSections(data *TestData) []funcSection {
+	var sections []funcSection
+	for _, kindSection := range kindSections {
+		if methods := getFuncsOfKind(data.methods, kindSection.kind, ""); methods != nil {
+			sections = append(sections, funcSection{title: kindSection.title, methods: methods})
 		}
-	case common:
+		if kindSection.kind != TestFunc {
+			continue
+		}
+		for i, suite := range data.suites {
+			if methods := getFuncsOfKind(data.methods, TestFunc, suite.name); methods != nil || suite.background != nil {
+				sections = append(sections, funcSection{"Suite " + suite.name, &data.suites[i], methods})
+			}
+		}
+	}
+	return sections
+}
+
+// getFuncsOfKind returns methods of the kind and the suite (empty for funcs) in the order of the source.
+func getFuncsOfKind(methods []TestMethod, kind FuncKind, suite string) []TestMethod {
+	var funcs []TestMethod
+	for _, method := range methods {
+		if method.kind == kind && method.suite == suite {
+			funcs = append(funcs, method)
+		}
+	}
+	return funcs
+}
+
+// getSectionsTOC returns TOC lines in the order of sections, numbered from 0.
+func getSectionsTOC(toc map[string]TOCLine, sections []funcSection) []TOCLine {
+	var lines []TOCLine
+	for _, section := range sections {
+		for _, method := range section.methods {
+			if line, ok := toc[method.name]; ok {
+				line.index = len(lines)
+				lines = append(lines, line)
+			}
+		}
+	}
+	return lines
+}
+
+// countResults returns counts of tests by result action, 'nil' if no results are applied.
+func countResults(methods []TestMethod) map[string]int {
+	var counts map[string]int
+	for _, method := range methods {
+		if method.result.action == "" {
+			continue
+		}
+		if counts == nil {
+			counts = make(map[string]int)
+		}
+		counts[method.result.action]++
+	}
+	return counts
+}
+
+// getSortedTOC returns TOC lines ordered by index.
+func getSortedTOC(toc map[string]TOCLine) []TOCLine {
+	var lines []TOCLine
+	for _, line := range toc {
+		lines = append(lines, line)
+	}
+	sort.Slice(lines, func(i, j int) bool { return lines[i].index < lines[j].index })
+	return lines
+}
+
+func getTopAnchor(packageName string) string {
+	if packageName == "" {
+		return "top"
+	}
+	return getAnchor("`" + packageName + "`")
+}
+
+func getTestsCount(count int) string {
+	if count == 1 {
+		return "1 test"
+	}
+	return strconv.Itoa(count) + " tests"
+}
+
+func getElapsed(result TestResult) string {
+	return strconv.FormatFloat(result.elapsed, 'f', 2, 64) + "s"
+}
+
+func getResultBadge(action string) string {
+	switch action {
+	case ActionPass:
 		{
-			return "- "
+			return "✅ PASS"
 		}
-	case indented:
+	case ActionFail:
 		{
-			return "  - "
+			return "❌ FAIL"
 		}
-	case indented2:
+	case ActionSkip:
 		{
-			return "    - "
+			return "⏭️ SKIP"
 		}
 	}
 	return ""
 }
 
-func appendScenario(scenario string, mdText *[]string) {
-	if scenario != "" {
-		*mdText = append(*mdText, "### "+scenario)
+// getResultsSummary returns counts of passed, failed and skipped tests as one line.
+func getResultsSummary(counts map[string]int, getBadge func(string) string) string {
+	var summary []string
+	for _, action := range []string{ActionPass, ActionFail, ActionSkip} {
+		summary = append(summary, getBadge(action)+" "+strconv.Itoa(counts[action]))
 	}
+	return strings.Join(summary, " · ")
+}
+
+// markdownMarkup is the MD format.
+type markdownMarkup struct{}
+
+func (markdownMarkup) appendDocument(title string, body []string, mdText *[]string) {
+	*mdText = append(*mdText, body...)
+}
+
+func (markdownMarkup) appendTitle(title string, mdText *[]string) {
+	*mdText = append(*mdText, "# "+title)
+}
+
+func (markdownMarkup) appendPackage(packageName string, mdText *[]string) {
+	*mdText = append(*mdText, "## `"+packageName+"`")
 }
 
-func appendFunc(name string, mdText *[]string) {
-	*mdText = append(*mdText, "---")
-	*mdText = append(*mdText, "#### `"+name+"`")
+// appendResultsSummary adds a line with counts of passed, failed and skipped tests.
+func (markdownMarkup) appendResultsSummary(counts map[string]int, mdText *[]string) {
+	*mdText = append(*mdText, getResultsSummary(counts, getResultBadge), "")
 }
 
-func appendFuncEnd(packageName string, mdText *[]string) {
+// appendTOC adds TOC lines as a numbered list of links.
+func (markdownMarkup) appendTOC(lines []TOCLine, mdText *[]string) {
+	for _, line := range lines {
+		*mdText = append(*mdText, strconv.Itoa(line.index+1)+". ["+line.caption+"]("+line.link+")")
+	}
 	*mdText = append(*mdText, "")
-	*mdText = append(*mdText, getLinkToTop(packageName))
 }
 
-func appendTags(tags []string, mdText *[]string) {
-	if tags == nil {
+func (markdownMarkup) appendSection(section string, mdText *[]string) {
+	*mdText = append(*mdText, "### "+section)
+}
+
+func (markdownMarkup) appendBackground(mdText *[]string) {
+	*mdText = append(*mdText, "#### Background")
+}
+
+func (markdownMarkup) appendFunc(name string, anchor string, depth int, mdText *[]string) {
+	if depth == 0 {
+		*mdText = append(*mdText, "---")
+	}
+	*mdText = append(*mdText, getHeaderPrefix(4+depth)+"`"+name+"`")
+}
+
+// appendFuncInfo adds a line with the test result and the link to the source, if any of them is known.
+func (markdownMarkup) appendFuncInfo(result TestResult, gitLink string, mdText *[]string) {
+	var info []string
+	if result.action != "" {
+		info = append(info, getResultBadge(result.action)+" "+getElapsed(result))
+	}
+	if gitLink != "" {
+		info = append(info, "[view source]("+gitLink+")")
+	}
+	if info != nil {
+		*mdText = append(*mdText, strings.Join(info, " · "))
+	}
+}
+
+func (markdownMarkup) appendTags(tags []string, mdText *[]string) {
+	*mdText = append(*mdText, "> "+strings.Join(tags, ", "))
+}
+
+func (markdownMarkup) appendScenario(scenario string, depth int, mdText *[]string) {
+	*mdText = append(*mdText, getHeaderPrefix(3+depth)+scenario)
+}
+
+func (markdownMarkup) appendDescription(description []string, mdText *[]string) {
+	*mdText = append(*mdText, description...)
+}
+
+func (markdownMarkup) appendSteps(steps []TestStep, depth int, mdText *[]string) {
+	for _, step := range steps {
+		prefix := getStepPrefix(step.kind)
+		if step.kind.IsGWT() {
+			prefix = getHeaderPrefix(4 + depth)
+		}
+		*mdText = append(*mdText, prefix+getStepText(step, getMDBold))
+	}
+}
+
+// appendCase adds a case of a table-driven test as a header with a table of its fields.
+func (markdownMarkup) appendCase(testCase TestCase, depth int, mdText *[]string) {
+	*mdText = append(*mdText, getHeaderPrefix(5+depth)+testCase.name)
+	if len(testCase.fields) == 0 {
 		return
 	}
-	var sep string
-	tagsLine := "> "
-	for _, tag := range tags {
-		tagsLine += sep + tag
-		sep = ", "
+	*mdText = append(*mdText, "| Field | Value |", "|---|---|")
+	for _, field := range testCase.fields {
+		*mdText = append(*mdText, "| "+field.name+" | "+getCodeCell(field.value)+" |")
+	}
+}
+
+// appendFailureOutput adds output of a failed test as a collapsible code block.
+func (markdownMarkup) appendFailureOutput(output []string, mdText *[]string) {
+	*mdText = append(*mdText, "<details><summary>Failure output</summary>", "", "```text")
+	*mdText = append(*mdText, output...)
+	*mdText = append(*mdText, "```", "", "</details>")
+}
+
+// appendOutput adds the expected output of an example as a code block.
+func (markdownMarkup) appendOutput(output []string, mdText *[]string) {
+	*mdText = append(*mdText, "Output:", "```text")
+	*mdText = append(*mdText, output...)
+	*mdText = append(*mdText, "```")
+}
+
+func (markdownMarkup) appendFuncEnd(topAnchor string, mdText *[]string) {
+	*mdText = append(*mdText, "")
+	*mdText = append(*mdText, "[top](#"+topAnchor+")")
+}
+
+func (markdownMarkup) appendIndex(items []indexItem, mdText *[]string) {
+	*mdText = append(*mdText, "## Packages")
+	for _, item := range items {
+		*mdText = append(*mdText, "- ["+item.caption+"]("+item.link+") - "+getTestsCount(item.count))
+	}
+}
+
+// getCodeCell returns the code as inline code for a table cell.
+func getCodeCell(code string) string {
+	code = strings.ReplaceAll(code, "|", "\\|")
+	if strings.Contains(code, "`") {
+		return "`` " + code + " ``"
 	}
-	*mdText = append(*mdText, tagsLine)
+	return "`" + code + "`"
 }
 
-func getLinkToTop(name string) string {
-	if name == "" {
-		name = "top"
+func getMDBold(text string) string {
+	return "**" + text + "**"
+}
+
+func getStepPrefix(kind StepKind) string {
+	switch kind {
+	case GWT:
+		{
+			return "#### "
+		}
+	case Common:
+		{
+			return "- "
+		}
+	case Indented:
+		{
+			return "  - "
+		}
+	case Indented2:
+		{
+			return "    - "
+		}
 	}
-	return "[top](#" + name + ")"
+	return ""
+}
+
+// getHeaderPrefix returns the prefix of a MD header of the level, the deepest level is 6.
+func getHeaderPrefix(level int) string {
+	return strings.Repeat("#", min(level, 6)) + " "
 }
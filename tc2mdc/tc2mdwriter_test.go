@@ -32,6 +32,26 @@ func TestWritePackageName(t *testing.T) {
 	}, mdText)
 }
 
+func TestWriteTitle(t *testing.T) {
+	// > Write to MD, Title
+	// # Write() returns "title" as header(1) above the package header
+	// ## GIVEN - testData: "title" = 'Some Title', "packageName" = 'SomePackage'
+	var testData = new(TestData)
+	testData.title = "Some Title"
+	testData.packageName = "SomePackage"
+
+	// ## WHEN Write()
+	mdText := Write(testData)
+
+	// ## THEN - MD text includes 2 lines:
+	require.Equal(t, []string{
+		// - "# Some Title"
+		"# Some Title",
+		// - "## `SomePackage`"
+		"## `SomePackage`",
+	}, mdText)
+}
+
 func TestWriteOneMethod(t *testing.T) {
 	// > Write to MD
 	// # Write() returns test method name as header(4) with separator before name and a link to the top after
@@ -77,16 +97,16 @@ func TestWritePackageTwoMethods(t *testing.T) {
 		"---",
 		// - "#### `TestSomething1`"
 		"#### `TestSomething1`",
-		// - "", "[top]#somePackage" - link to the package line
+		// - "", "[top]#somepackage" - link to the GitHub anchor of the package line
 		"",
-		"[top](#somePackage)",
+		"[top](#somepackage)",
 		// - "---"
 		"---",
 		// - "#### `TestSomething2`"
 		"#### `TestSomething2`",
-		// - "", "[top]#somePackage" - link to the package line
+		// - "", "[top]#somepackage" - link to the GitHub anchor of the package line
 		"",
-		"[top](#somePackage)",
+		"[top](#somepackage)",
 	}, mdText)
 }
 
@@ -140,6 +160,36 @@ func TestWriteMethodScenario(t *testing.T) {
 	}, mdText)
 }
 
+func TestWriteMethodDescription(t *testing.T) {
+	// > Write to MD
+	// # Write() returns "description" lines between the scenario and steps
+	// ## GIVEN - testData: "packageName" = ''
+	var testData = new(TestData)
+	// - 1 element in "methods": "name" = 'TestSomething', "scenario" = 'Something happens'
+	testData.methods = append(testData.methods, TestMethod{name: "TestSomething", scenario: "Something happens"})
+	// - "description" = 'Some intent', 'in 2 lines'
+	testData.methods[0].description = []string{"Some intent", "in 2 lines"}
+	// - 1 step: common 'Step1'
+	testData.methods[0].steps = []TestStep{{kind: Common, comment: "Step1"}}
+
+	// ## WHEN Write()
+	mdText := Write(testData)
+
+	// ## THEN - MD text includes description lines after the scenario line:
+	require.Equal(t, []string{
+		"---",
+		"#### `TestSomething`",
+		"### Something happens",
+		// - "Some intent"
+		"Some intent",
+		// - "in 2 lines"
+		"in 2 lines",
+		"- Step1",
+		"",
+		"[top](#top)",
+	}, mdText)
+}
+
 func TestWriteMethodGWT(t *testing.T) {
 	// > Write to MD
 	// # Write() returns 'GWT' comments as header(4) lines
@@ -171,6 +221,74 @@ func TestWriteMethodGWT(t *testing.T) {
 	}, mdText)
 }
 
+func TestWriteMethodCases(t *testing.T) {
+	// > Write to MD, Table-driven
+	// # Write() returns "cases" as header(5) lines with tables of fields after steps
+	// ## GIVEN - testData: "packageName" = ''
+	var testData = new(TestData)
+	// - 1 element in "methods": "name" = 'TestSomething'
+	testData.methods = append(testData.methods, TestMethod{name: "TestSomething"})
+	// - 2 cases: 'empty' without fields, 'pipe' with fields {'in', '"a|b"'}, {'raw', '`x`'}
+	testData.methods[0].cases = []TestCase{
+		{name: "empty"},
+		{name: "pipe", fields: []TestField{{"in", `"a|b"`}, {"raw", "`x`"}}},
+	}
+
+	// ## WHEN Write()
+	mdText := Write(testData)
+
+	// ## THEN - MD text includes cases:
+	require.Equal(t, []string{
+		"---",
+		"#### `TestSomething`",
+		// - "##### empty"
+		"##### empty",
+		// - "##### pipe" and a table with escaped '|' and '`'
+		"##### pipe",
+		"| Field | Value |",
+		"|---|---|",
+		"| in | `\"a\\|b\"` |",
+		"| raw | `` `x` `` |",
+		"",
+		"[top](#top)",
+	}, mdText)
+}
+
+func TestWriteMethodSubtests(t *testing.T) {
+	// > Write to MD, Subtests
+	// # Write() returns subtests as nested headers one level deeper than the parent ones
+	// ## GIVEN - testData: "packageName" = ''
+	var testData = new(TestData)
+	// - 1 element in "methods": "name" = 'TestParent', 1 'GWT' step "WHEN act"
+	testData.methods = append(testData.methods, TestMethod{name: "TestParent",
+		steps: []TestStep{{kind: GWT, comment: "WHEN act"}}})
+	// - 1 subtest 'TestParent/child' with "tags", "scenario", a 'GWT' step and a common step
+	testData.methods[0].subtests = []TestMethod{{name: "TestParent/child", tags: []string{"Tag"}, scenario: "Child",
+		steps: []TestStep{{kind: GWT, comment: "THEN check"}, {kind: Common, comment: "Step"}}}}
+
+	// ## WHEN Write()
+	mdText := Write(testData)
+
+	// ## THEN - MD text includes the subtest after the parent steps:
+	require.Equal(t, []string{
+		"---",
+		"#### `TestParent`",
+		"#### WHEN act",
+		// - "##### `TestParent/child`"
+		"##### `TestParent/child`",
+		// - "> Tag"
+		"> Tag",
+		// - "#### Child" - scenario as header(4)
+		"#### Child",
+		// - "##### THEN check" - 'GWT' step as header(5)
+		"##### THEN check",
+		// - "- Step"
+		"- Step",
+		"",
+		"[top](#top)",
+	}, mdText)
+}
+
 func TestWriteMethodIndentedSteps(t *testing.T) {
 	// > Write to MD
 	// # Write() returns indented comments as indented lines prefixed with '-'
@@ -179,9 +297,9 @@ func TestWriteMethodIndentedSteps(t *testing.T) {
 	// - 1 element in "methods": "name" = 'TestSomething'
 	testData.methods = append(testData.methods, TestMethod{name: "TestSomething"})
 	// - 3 steps: common 'Step1', indented 'Step2', indented twice 'Step3'
-	testData.methods[0].steps = append(testData.methods[0].steps, TestStep{kind: common, comment: "Step1"})
-	testData.methods[0].steps = append(testData.methods[0].steps, TestStep{kind: indented, comment: "Step2"})
-	testData.methods[0].steps = append(testData.methods[0].steps, TestStep{kind: indented2, comment: "Step3"})
+	testData.methods[0].steps = append(testData.methods[0].steps, TestStep{kind: Common, comment: "Step1"})
+	testData.methods[0].steps = append(testData.methods[0].steps, TestStep{kind: Indented, comment: "Step2"})
+	testData.methods[0].steps = append(testData.methods[0].steps, TestStep{kind: Indented2, comment: "Step3"})
 
 	// ## WHEN Write()
 	mdText := Write(testData)
@@ -201,3 +319,274 @@ func TestWriteMethodIndentedSteps(t *testing.T) {
 		"[top](#top)",
 	}, mdText)
 }
+
+func TestWriteIndex(t *testing.T) {
+	// > Write to MD, Packages
+	// # WriteIndex() returns a list of links to package documents with tests count
+	// ## GIVEN - 2 packages: 'pkg1' with 1 method, 'pkg2' with "title" = 'Package 2' and 2 methods
+	var packages = []*TestData{
+		{packageName: "pkg1", methods: []TestMethod{{name: "TestA"}}},
+		{packageName: "pkg2", title: "Package 2", methods: []TestMethod{{name: "TestB"}, {name: "TestC"}}},
+	}
+	// - links: 'pkg1.md', 'sub/pkg2.md'
+	var links = []string{"pkg1.md", "sub/pkg2.md"}
+
+	// ## WHEN WriteIndex()
+	mdText := WriteIndex(packages, links)
+
+	// ## THEN - MD text includes a header and 2 lines:
+	require.Equal(t, []string{
+		// - "## Packages"
+		"## Packages",
+		// - "- [`pkg1`](pkg1.md) - 1 test"
+		"- [`pkg1`](pkg1.md) - 1 test",
+		// - "- [Package 2](sub/pkg2.md) - 2 tests" - named by the title
+		"- [Package 2](sub/pkg2.md) - 2 tests",
+	}, mdText)
+}
+
+func TestWriteTOC(t *testing.T) {
+	// > Write to MD, TOC
+	// # Write() returns "TOC" lines as a numbered list of links after the package header
+	// ## GIVEN - testData: "packageName" = 'pkg'
+	var testData = new(TestData)
+	testData.packageName = "pkg"
+	// - 2 "methods": 'TestA', 'TestB'
+	testData.methods = []TestMethod{{name: "TestA"}, {name: "TestB"}}
+	// - 2 TOC lines: {1, '`TestB`', '#testb'}, {0, 'A happens', '#testa'}
+	testData.toc = map[string]TOCLine{
+		"TestB": {index: 1, caption: "`TestB`", link: "#testb"},
+		"TestA": {index: 0, caption: "A happens", link: "#testa"},
+	}
+
+	// ## WHEN Write()
+	mdText := Write(testData)
+
+	// ## THEN - MD text starts with the package header and TOC lines ordered by "index":
+	require.Equal(t, []string{
+		"## `pkg`",
+		// - "1. [A happens](#testa)"
+		"1. [A happens](#testa)",
+		// - "2. [`TestB`](#testb)"
+		"2. [`TestB`](#testb)",
+		// - "" // to separate TOC from the first method
+		"",
+	}, mdText[:4])
+}
+
+func TestWriteSourceLink(t *testing.T) {
+	// > Write to MD, Git
+	// # Write() returns a link to the source after the method name when "TOC" line has "gitLink"
+	// ## GIVEN - testData: "packageName" = ''
+	var testData = new(TestData)
+	// - 1 element in "methods": "name" = 'TestSomething'
+	testData.methods = append(testData.methods, TestMethod{name: "TestSomething"})
+	// - TOC line of 'TestSomething' with "gitLink" = 'https://host/repo/blob/main/a_test.go#L3'
+	testData.toc = map[string]TOCLine{
+		"TestSomething": {index: 0, caption: "`TestSomething`", link: "#testsomething",
+			gitLink: "https://host/repo/blob/main/a_test.go#L3"},
+	}
+
+	// ## WHEN Write()
+	mdText := Write(testData)
+
+	// ## THEN - MD text includes the link line after method name line:
+	require.Equal(t, []string{
+		"1. [`TestSomething`](#testsomething)",
+		"",
+		"---",
+		// - "#### `TestSomething`"
+		"#### `TestSomething`",
+		// - "[view source](https://host/repo/blob/main/a_test.go#L3)"
+		"[view source](https://host/repo/blob/main/a_test.go#L3)",
+		"",
+		"[top](#top)",
+	}, mdText)
+}
+
+func TestWriteResults(t *testing.T) {
+	// > Write to MD, Test results
+	// # Write() returns the results summary after the package header and a result line after each test header
+	// ## GIVEN - testData: "packageName" = 'pkg'
+	var testData = new(TestData)
+	testData.packageName = "pkg"
+	// - 'TestA' passed in 0.5s with a subtest 'TestA/child' skipped
+	// - 'TestB' failed in 1.234s, 'TestC' without a result
+	testData.methods = []TestMethod{
+		{name: "TestA", result: TestResult{action: ActionPass, elapsed: 0.5},
+			subtests: []TestMethod{{name: "TestA/child", result: TestResult{action: ActionSkip}}}},
+		{name: "TestB", result: TestResult{action: ActionFail, elapsed: 1.234}},
+		{name: "TestC"},
+	}
+
+	// ## WHEN Write()
+	mdText := Write(testData)
+
+	// ## THEN - MD text includes:
+	require.Equal(t, []string{
+		"## `pkg`",
+		// - "✅ PASS 1 · ❌ FAIL 1 · ⏭️ SKIP 0" - counts of top level tests
+		"✅ PASS 1 · ❌ FAIL 1 · ⏭️ SKIP 0",
+		"",
+		"---",
+		"#### `TestA`",
+		// - "✅ PASS 0.50s"
+		"✅ PASS 0.50s",
+		"##### `TestA/child`",
+		// - "⏭️ SKIP 0.00s"
+		"⏭️ SKIP 0.00s",
+		"",
+		"[top](#pkg)",
+		"---",
+		"#### `TestB`",
+		// - "❌ FAIL 1.23s"
+		"❌ FAIL 1.23s",
+		"",
+		"[top](#pkg)",
+		"---",
+		"#### `TestC`",
+		"",
+		"[top](#pkg)",
+	}, mdText)
+}
+
+func TestWriteFailureOutput(t *testing.T) {
+	// > Write to MD, Test results
+	// # Write() returns output of a failed test as a collapsible block after its steps
+	// ## GIVEN - testData: "packageName" = ''
+	var testData = new(TestData)
+	// - 'TestA' failed with a step and 2 output lines
+	testData.methods = []TestMethod{{name: "TestA", steps: []TestStep{{kind: Common, comment: "Step"}},
+		result: TestResult{action: ActionFail, output: []string{"a_test.go:5:", "Error: Not equal"}}}}
+
+	// ## WHEN Write()
+	mdText := Write(testData)
+
+	// ## THEN - MD text includes:
+	require.Equal(t, []string{
+		"✅ PASS 0 · ❌ FAIL 1 · ⏭️ SKIP 0",
+		"",
+		"---",
+		"#### `TestA`",
+		"❌ FAIL 0.00s",
+		"- Step",
+		// - "<details><summary>Failure output</summary>"
+		"<details><summary>Failure output</summary>",
+		"",
+		// - output lines as a code block
+		"```text",
+		"a_test.go:5:",
+		"Error: Not equal",
+		"```",
+		"",
+		// - "</details>"
+		"</details>",
+		"",
+		"[top](#top)",
+	}, mdText)
+}
+
+func TestWriterFormats(t *testing.T) {
+	// > Writers
+	// # GetWriter() returns writers of all formats and error on an unknown format
+	// ## WHEN Formats()
+	formats := Formats()
+	// ## THEN formats are 'adoc', 'gherkin', 'html', 'json', 'md', 'txt', 'yaml'
+	require.Equal(t, []string{"adoc", "gherkin", "html", "json", "md", "txt", "yaml"}, formats)
+	// ## WHEN GetWriter('md')
+	writer, err := GetWriter("md")
+	// ## THEN no error, the writer has '.md' extension
+	require.Nil(t, err, "must be no error")
+	require.Equal(t, ".md", writer.Extension())
+	// ## WHEN GetWriter('pdf')
+	writer, err = GetWriter("pdf")
+	// ## THEN error 'unknown format: pdf', writer is 'nil'
+	require.Nil(t, writer, "writer must be nil")
+	require.ErrorContains(t, err, "unknown format: pdf")
+}
+
+func TestWriteAll(t *testing.T) {
+	// > Write to MD, Writers
+	// # WriteAll() returns one document of several packages in order
+	// ## GIVEN - 2 packages 'pkg1', 'pkg2' and 'nil'
+	var packages = []*TestData{{packageName: "pkg1"}, nil, {packageName: "pkg2"}}
+	writer, _ := GetWriter("md")
+
+	// ## WHEN WriteAll()
+	mdText := writer.WriteAll(packages)
+
+	// ## THEN - MD text includes 2 package headers:
+	require.Equal(t, []string{"## `pkg1`", "## `pkg2`"}, mdText)
+}
+
+func TestWriteFuncSections(t *testing.T) {
+	// > Write to MD
+	// # Write() puts tests, benchmarks and examples in sections with the TOC in the same order
+	// ## GIVEN - testData: methods are an example, a test and a benchmark
+	var testData = &TestData{methods: []TestMethod{
+		{name: "ExampleA", kind: ExampleFunc, output: []string{"a"}},
+		{name: "TestA"},
+		{name: "BenchmarkA", kind: BenchmarkFunc},
+	}}
+	fillTOC(testData)
+
+	// ## WHEN Write()
+	mdText := Write(testData)
+
+	// ## THEN MD text is:
+	require.Equal(t, []string{
+		// - the TOC in the order of sections
+		"1. [`TestA`](#testa)",
+		"2. [`BenchmarkA`](#benchmarka)",
+		"3. [`ExampleA`](#examplea)",
+		"",
+		// - sections "Tests", "Benchmarks" and "Examples"
+		"### Tests",
+		"---",
+		"#### `TestA`",
+		"",
+		"[top](#top)",
+		"### Benchmarks",
+		"---",
+		"#### `BenchmarkA`",
+		"",
+		"[top](#top)",
+		"### Examples",
+		"---",
+		"#### `ExampleA`",
+		// - the output of the example as a code block
+		"Output:",
+		"```text",
+		"a",
+		"```",
+		"",
+		"[top](#top)",
+	}, mdText)
+}
+
+func TestWriteBackground(t *testing.T) {
+	// > Background, Write to MD
+	// # Write() returns the background once before the first test
+	// ## GIVEN - testData: "packageName" = 'pkg', a background step and 2 methods
+	var testData = &TestData{packageName: "pkg", background: []TestStep{{Given, "started server"}},
+		methods: []TestMethod{{name: "TestA"}, {name: "TestB"}}}
+
+	// ## WHEN Write()
+	mdText := Write(testData)
+
+	// ## THEN MD text is:
+	require.Equal(t, []string{
+		"## `pkg`",
+		// - the background header and its steps one level deeper
+		"#### Background",
+		"##### **GIVEN** started server",
+		"---",
+		"#### `TestA`",
+		"",
+		"[top](#pkg)",
+		"---",
+		"#### `TestB`",
+		"",
+		"[top](#pkg)",
+	}, mdText)
+}
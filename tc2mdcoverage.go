@@ -0,0 +1,71 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"tc2mdc"
+)
+
+const coverageUsage = `Report how many test funcs are documented with a scenario and steps per package.
+
+Usage:
+  tc2md coverage [flags] [path ...]
+
+Paths are the same as of the conversion. The exit code is 1 if the total
+coverage is below -min.
+
+Flags:
+`
+
+// coverage prints or saves the coverage report of test files and exits with 1 if it is below the minimum.
+func coverage(args []string) {
+	flags := flag.NewFlagSet("coverage", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), coverageUsage)
+		flags.PrintDefaults()
+	}
+	output := flags.String("o", "", "output `file`, stdout by default")
+	format := flags.String("format", "md", "report format: md, json")
+	minPercent := flags.Float64("min", 0, "minimum total coverage in `percent`")
+	_ = flags.Parse(args)
+	if *format != "md" && *format != "json" {
+		log.Fatal("unknown format: " + *format)
+	}
+
+	paths := flags.Args()
+	if len(paths) == 0 {
+		paths = []string{"./..."}
+	}
+	testFiles, roots, err := collectTestFiles(paths)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if len(testFiles) == 0 {
+		log.Fatal("no test files found")
+	}
+
+	var packages []*tc2mdc.TestData
+	for _, doc := range getPackageDocuments("", "", testFiles, roots) {
+		packages = append(packages, doc.data)
+	}
+	report := tc2mdc.GetCoverage(packages)
+	text := report.Markdown()
+	if *format == "json" {
+		text = report.JSON()
+	}
+	if *output == "" {
+		fmt.Println(strings.Join(text, "\n"))
+	} else {
+		saveToFile(*output, text)
+	}
+
+	if report.Total.Percent < *minPercent {
+		log.Printf("coverage %s%% is below the minimum %s%%", strconv.FormatFloat(report.Total.Percent, 'f', 1, 64),
+			strconv.FormatFloat(*minPercent, 'f', -1, 64))
+		os.Exit(1)
+	}
+}
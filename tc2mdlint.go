@@ -0,0 +1,61 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+)
+
+const lintUsage = `Report tests without scenarios or steps and malformed markers.
+
+Usage:
+  tc2md lint [path ...]
+
+Paths are the same as of the conversion. Issues are printed as "file:line: message",
+the exit code is 1 if there are any.
+`
+
+// lint prints issues of test files and exits with 1 if there are any.
+func lint(args []string) {
+	flags := flag.NewFlagSet("lint", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), lintUsage)
+		flags.PrintDefaults()
+	}
+	_ = flags.Parse(args)
+
+	paths := flags.Args()
+	if len(paths) == 0 {
+		paths = []string{"./..."}
+	}
+	testFiles, _, err := collectTestFiles(paths)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if len(testFiles) == 0 {
+		log.Fatal("no test files found")
+	}
+
+	count := 0
+	for _, testFile := range testFiles {
+		code, err := os.ReadFile(testFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		issues, err := getGrammar(testFile).Lint(testFile, code)
+		if err != nil {
+			fmt.Println(err)
+			count++
+			continue
+		}
+		for _, issue := range issues {
+			fmt.Println(issue)
+		}
+		count += len(issues)
+	}
+	if count != 0 {
+		log.Printf("%d issues found", count)
+		os.Exit(1)
+	}
+}